@@ -51,6 +51,10 @@ func main() {
 		slog.String("advertised_address", cfg.App.AdvertisedAddress),
 		slog.String("cache_mode", string(cfg.Cache.Mode)),
 		slog.Duration("cache_ttl", cfg.Cache.TTL),
+		slog.String("dns_mode", string(cfg.DNS.Mode)),
+		slog.Any("dns_servers", cfg.DNS.Servers),
+		slog.String("registration_mode", string(cfg.Registration.Mode)),
+		slog.Any("mmdb_files", cfg.Enrichment.MMDBFiles),
 		slog.String("log_level", cfg.Log.Level),
 		slog.String("log_format", cfg.Log.Format))
 
@@ -16,6 +16,14 @@ type Config struct {
 	Cache CacheConfig `json:"cache"`
 	// Logging configuration
 	Log LogConfig `json:"log"`
+	// DNS resolver configuration
+	DNS DNSConfig `json:"dns"`
+	// Domain registration lookup configuration
+	Registration RegistrationConfig `json:"registration"`
+	// Offline IP enrichment configuration
+	Enrichment EnrichmentConfig `json:"enrichment"`
+	// Security findings configuration
+	Findings FindingsConfig `json:"findings"`
 }
 
 type AppConfig struct {
@@ -49,6 +57,54 @@ type CacheConfig struct {
 	TTL time.Duration `json:"ttl"`
 }
 
+type ResolverMode string
+
+const (
+	ResolverModeSystem   ResolverMode = "system"
+	ResolverModeUpstream ResolverMode = "upstream"
+	ResolverModeDoT      ResolverMode = "dot"
+	ResolverModeDoH      ResolverMode = "doh"
+)
+
+type DNSConfig struct {
+	// Resolver used for every DNS lookup: "system", "upstream", "dot" or "doh"
+	Mode ResolverMode `json:"mode"`
+	// Upstream servers, host[:port] for "upstream" and "dot", https URLs for "doh"
+	Servers []string `json:"servers"`
+	// Timeout for a single DNS query
+	Timeout time.Duration `json:"timeout"`
+	// Keep the records of successful zone transfers in the report, not just their count
+	KeepZoneTransfers bool `json:"keep_zone_transfers"`
+	// Optional Public Suffix List file used instead of the bundled copy
+	PublicSuffixListFile string `json:"public_suffix_list_file"`
+}
+
+type RegistrationMode string
+
+const (
+	RegistrationModeRDAP  RegistrationMode = "rdap"
+	RegistrationModeWHOIS RegistrationMode = "whois"
+)
+
+type RegistrationConfig struct {
+	// Registration data source: "rdap" (falling back to WHOIS) or "whois"
+	Mode RegistrationMode `json:"mode"`
+	// Optional IANA RDAP bootstrap file used instead of the bundled copy
+	RDAPBootstrapFile string `json:"rdap_bootstrap_file"`
+	// How often the RDAP bootstrap is refreshed from IANA, zero disables refreshing
+	RDAPBootstrapRefresh time.Duration `json:"rdap_bootstrap_refresh"`
+}
+
+type EnrichmentConfig struct {
+	// Local MaxMind/IPinfo .mmdb files used to annotate addresses with ASN, prefix and country
+	MMDBFiles []string `json:"mmdb_files"`
+}
+
+type FindingsConfig struct {
+	// Optional subdomain takeover fingerprint table used instead of the bundled one
+	TakeoverFingerprintsFile string `json:"takeover_fingerprints_file"`
+}
+
 type LogConfig struct {
 	// Log level: debug, info, warn, error
 	Level string `json:"level"`
@@ -73,6 +129,14 @@ func Load() (*Config, error) {
 			Level:  "info",
 			Format: "text",
 		},
+		DNS: DNSConfig{
+			Mode:    ResolverModeSystem,
+			Timeout: 3 * time.Second,
+		},
+		Registration: RegistrationConfig{
+			Mode:                 RegistrationModeRDAP,
+			RDAPBootstrapRefresh: 24 * time.Hour,
+		},
 	}
 
 	// Load from environment variables first
@@ -139,6 +203,62 @@ func (c *Config) loadFromEnv() error {
 		c.Log.Format = strings.ToLower(format)
 	}
 
+	// DNS configuration
+	if mode := os.Getenv("NSDIGUP_DNS_MODE"); mode != "" {
+		c.DNS.Mode = ResolverMode(strings.ToLower(mode))
+	}
+
+	if servers := os.Getenv("NSDIGUP_DNS_SERVERS"); servers != "" {
+		c.DNS.Servers = splitList(servers)
+	}
+
+	if timeout := os.Getenv("NSDIGUP_DNS_TIMEOUT"); timeout != "" {
+		duration, err := time.ParseDuration(timeout)
+		if err != nil {
+			return fmt.Errorf("invalid NSDIGUP_DNS_TIMEOUT value '%s': %w", timeout, err)
+		}
+		c.DNS.Timeout = duration
+	}
+
+	if keep := os.Getenv("NSDIGUP_KEEP_ZONE_TRANSFERS"); keep != "" {
+		b, err := strconv.ParseBool(keep)
+		if err != nil {
+			return fmt.Errorf("invalid NSDIGUP_KEEP_ZONE_TRANSFERS value '%s': %w", keep, err)
+		}
+		c.DNS.KeepZoneTransfers = b
+	}
+
+	if file := os.Getenv("NSDIGUP_PUBLIC_SUFFIX_LIST_FILE"); file != "" {
+		c.DNS.PublicSuffixListFile = file
+	}
+
+	// Registration configuration
+	if mode := os.Getenv("NSDIGUP_REGISTRATION_MODE"); mode != "" {
+		c.Registration.Mode = RegistrationMode(strings.ToLower(mode))
+	}
+
+	if file := os.Getenv("NSDIGUP_RDAP_BOOTSTRAP_FILE"); file != "" {
+		c.Registration.RDAPBootstrapFile = file
+	}
+
+	if refresh := os.Getenv("NSDIGUP_RDAP_BOOTSTRAP_REFRESH"); refresh != "" {
+		duration, err := time.ParseDuration(refresh)
+		if err != nil {
+			return fmt.Errorf("invalid NSDIGUP_RDAP_BOOTSTRAP_REFRESH value '%s': %w", refresh, err)
+		}
+		c.Registration.RDAPBootstrapRefresh = duration
+	}
+
+	// Enrichment configuration
+	if files := os.Getenv("NSDIGUP_MMDB_FILES"); files != "" {
+		c.Enrichment.MMDBFiles = splitList(files)
+	}
+
+	// Findings configuration
+	if file := os.Getenv("NSDIGUP_TAKEOVER_FINGERPRINTS_FILE"); file != "" {
+		c.Findings.TakeoverFingerprintsFile = file
+	}
+
 	return nil
 }
 
@@ -152,6 +272,16 @@ func (c *Config) loadFromFlags() error {
 			cacheTTL          = flag.Duration("cache-ttl", c.Cache.TTL, "Cache TTL duration (e.g., 5m, 1h)")
 			logLevel          = flag.String("log-level", c.Log.Level, "Log level: debug, info, warn, error")
 			logFormat         = flag.String("log-format", c.Log.Format, "Log format: text, json")
+			dnsMode           = flag.String("dns-mode", string(c.DNS.Mode), "DNS resolver mode: 'system', 'upstream', 'dot' or 'doh'")
+			dnsServers        = flag.String("dns-servers", strings.Join(c.DNS.Servers, ","), "Comma separated DNS servers (host:port, or https URLs for doh)")
+			dnsTimeout        = flag.Duration("dns-timeout", c.DNS.Timeout, "Timeout for a single DNS query (e.g., 3s)")
+			keepZoneTransfers = flag.Bool("keep-zone-transfers", c.DNS.KeepZoneTransfers, "Include records of successful zone transfers in the report")
+			pslFile           = flag.String("public-suffix-list-file", c.DNS.PublicSuffixListFile, "Public Suffix List file to use instead of the bundled copy")
+			registrationMode  = flag.String("registration-mode", string(c.Registration.Mode), "Registration data source: 'rdap' (with WHOIS fallback) or 'whois'")
+			rdapBootstrapFile = flag.String("rdap-bootstrap-file", c.Registration.RDAPBootstrapFile, "IANA RDAP bootstrap file to use instead of the bundled copy")
+			rdapRefresh       = flag.Duration("rdap-bootstrap-refresh", c.Registration.RDAPBootstrapRefresh, "How often to refresh the RDAP bootstrap from IANA (0 disables)")
+			takeoverFile      = flag.String("takeover-fingerprints-file", c.Findings.TakeoverFingerprintsFile, "Subdomain takeover fingerprint table to use instead of the bundled one")
+			mmdbFiles         = flag.String("mmdb-files", strings.Join(c.Enrichment.MMDBFiles, ","), "Comma separated MaxMind/IPinfo .mmdb files for ASN and country enrichment")
 		)
 
 		flag.Parse()
@@ -162,6 +292,16 @@ func (c *Config) loadFromFlags() error {
 		c.Cache.TTL = *cacheTTL
 		c.Log.Level = strings.ToLower(*logLevel)
 		c.Log.Format = strings.ToLower(*logFormat)
+		c.DNS.Mode = ResolverMode(strings.ToLower(*dnsMode))
+		c.DNS.Servers = splitList(*dnsServers)
+		c.DNS.Timeout = *dnsTimeout
+		c.DNS.KeepZoneTransfers = *keepZoneTransfers
+		c.DNS.PublicSuffixListFile = *pslFile
+		c.Registration.Mode = RegistrationMode(strings.ToLower(*registrationMode))
+		c.Registration.RDAPBootstrapFile = *rdapBootstrapFile
+		c.Registration.RDAPBootstrapRefresh = *rdapRefresh
+		c.Enrichment.MMDBFiles = splitList(*mmdbFiles)
+		c.Findings.TakeoverFingerprintsFile = *takeoverFile
 
 		switch CacheMode(*cacheMode) {
 		case CacheModeNone:
@@ -176,6 +316,17 @@ func (c *Config) loadFromFlags() error {
 	return nil
 }
 
+// splitList splits a comma separated value, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
 // isTest checks if we're running in test mode - just to avoid issues when parsing flags
 func isTest() bool {
 	for _, arg := range os.Args {
@@ -216,6 +367,41 @@ func (c *Config) validate() error {
 		return fmt.Errorf("invalid log format '%s': must be text or json", c.Log.Format)
 	}
 
+	// Validate DNS resolver, an empty mode falls back to the system resolver
+	switch c.DNS.Mode {
+	case "", ResolverModeSystem:
+	case ResolverModeUpstream, ResolverModeDoT:
+		if len(c.DNS.Servers) == 0 {
+			return fmt.Errorf("DNS mode '%s' requires at least one server", c.DNS.Mode)
+		}
+	case ResolverModeDoH:
+		if len(c.DNS.Servers) == 0 {
+			return fmt.Errorf("DNS mode '%s' requires at least one server", c.DNS.Mode)
+		}
+		for _, server := range c.DNS.Servers {
+			if !strings.HasPrefix(server, "https://") {
+				return fmt.Errorf("invalid DoH server '%s': must be an https URL", server)
+			}
+		}
+	default:
+		return fmt.Errorf("invalid DNS mode '%s': must be system, upstream, dot or doh", c.DNS.Mode)
+	}
+
+	if c.DNS.Timeout < 0 {
+		return fmt.Errorf("DNS timeout cannot be negative")
+	}
+
+	// Validate registration lookup, an empty mode falls back to RDAP
+	switch c.Registration.Mode {
+	case "", RegistrationModeRDAP, RegistrationModeWHOIS:
+	default:
+		return fmt.Errorf("invalid registration mode '%s': must be rdap or whois", c.Registration.Mode)
+	}
+
+	if c.Registration.RDAPBootstrapRefresh < 0 {
+		return fmt.Errorf("RDAP bootstrap refresh cannot be negative")
+	}
+
 	return nil
 }
 
@@ -110,6 +110,157 @@ func TestConfig_LoadFromEnv_InvalidDuration(t *testing.T) {
 	}
 }
 
+func TestConfig_LoadFromEnv_DNS(t *testing.T) {
+	clearEnv()
+	resetFlags()
+
+	os.Setenv("NSDIGUP_DNS_MODE", "upstream")
+	os.Setenv("NSDIGUP_DNS_SERVERS", "10.0.0.53, 10.0.1.53:5353")
+	os.Setenv("NSDIGUP_DNS_TIMEOUT", "2s")
+	os.Setenv("NSDIGUP_KEEP_ZONE_TRANSFERS", "true")
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if cfg.DNS.Mode != ResolverModeUpstream {
+		t.Errorf("Expected DNS mode 'upstream', got '%s'", cfg.DNS.Mode)
+	}
+
+	if len(cfg.DNS.Servers) != 2 || cfg.DNS.Servers[0] != "10.0.0.53" || cfg.DNS.Servers[1] != "10.0.1.53:5353" {
+		t.Errorf("Unexpected DNS servers: %v", cfg.DNS.Servers)
+	}
+
+	if cfg.DNS.Timeout != 2*time.Second {
+		t.Errorf("Expected DNS timeout '2s', got '%v'", cfg.DNS.Timeout)
+	}
+
+	if !cfg.DNS.KeepZoneTransfers {
+		t.Error("Expected zone transfer records to be kept")
+	}
+}
+
+func TestConfig_LoadFromEnv_InvalidDNS(t *testing.T) {
+	tests := []struct {
+		name    string
+		mode    string
+		servers string
+	}{
+		{name: "unknown mode", mode: "carrier-pigeon"},
+		{name: "upstream without servers", mode: "upstream"},
+		{name: "doh with plain address", mode: "doh", servers: "1.1.1.1:53"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv()
+			resetFlags()
+
+			os.Setenv("NSDIGUP_DNS_MODE", tt.mode)
+			if tt.servers != "" {
+				os.Setenv("NSDIGUP_DNS_SERVERS", tt.servers)
+			}
+			defer clearEnv()
+
+			if _, err := Load(); err == nil {
+				t.Error("Expected error for invalid DNS configuration")
+			}
+		})
+	}
+}
+
+func TestConfig_LoadFromEnv_Registration(t *testing.T) {
+	clearEnv()
+	resetFlags()
+
+	os.Setenv("NSDIGUP_REGISTRATION_MODE", "whois")
+	os.Setenv("NSDIGUP_RDAP_BOOTSTRAP_FILE", "/etc/nsdigup/dns.json")
+	os.Setenv("NSDIGUP_RDAP_BOOTSTRAP_REFRESH", "0")
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if cfg.Registration.Mode != RegistrationModeWHOIS {
+		t.Errorf("Expected registration mode 'whois', got '%s'", cfg.Registration.Mode)
+	}
+
+	if cfg.Registration.RDAPBootstrapFile != "/etc/nsdigup/dns.json" {
+		t.Errorf("Unexpected RDAP bootstrap file: %s", cfg.Registration.RDAPBootstrapFile)
+	}
+
+	if cfg.Registration.RDAPBootstrapRefresh != 0 {
+		t.Errorf("Expected refresh disabled, got '%v'", cfg.Registration.RDAPBootstrapRefresh)
+	}
+}
+
+func TestConfig_LoadFromEnv_MMDBFiles(t *testing.T) {
+	clearEnv()
+	resetFlags()
+
+	os.Setenv("NSDIGUP_MMDB_FILES", "/var/lib/GeoLite2-ASN.mmdb, /var/lib/GeoLite2-Country.mmdb")
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if len(cfg.Enrichment.MMDBFiles) != 2 || cfg.Enrichment.MMDBFiles[1] != "/var/lib/GeoLite2-Country.mmdb" {
+		t.Errorf("Unexpected MMDB files: %v", cfg.Enrichment.MMDBFiles)
+	}
+}
+
+func TestConfig_LoadFromEnv_TakeoverFingerprints(t *testing.T) {
+	clearEnv()
+	resetFlags()
+
+	os.Setenv("NSDIGUP_TAKEOVER_FINGERPRINTS_FILE", "/etc/nsdigup/takeover.json")
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if cfg.Findings.TakeoverFingerprintsFile != "/etc/nsdigup/takeover.json" {
+		t.Errorf("Unexpected takeover fingerprints file: %s", cfg.Findings.TakeoverFingerprintsFile)
+	}
+}
+
+func TestConfig_LoadFromEnv_PublicSuffixList(t *testing.T) {
+	clearEnv()
+	resetFlags()
+
+	os.Setenv("NSDIGUP_PUBLIC_SUFFIX_LIST_FILE", "/etc/nsdigup/public_suffix_list.dat")
+	defer clearEnv()
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if cfg.DNS.PublicSuffixListFile != "/etc/nsdigup/public_suffix_list.dat" {
+		t.Errorf("Unexpected public suffix list file: %s", cfg.DNS.PublicSuffixListFile)
+	}
+}
+
+func TestConfig_LoadFromEnv_InvalidRegistrationMode(t *testing.T) {
+	clearEnv()
+	resetFlags()
+
+	os.Setenv("NSDIGUP_REGISTRATION_MODE", "finger")
+	defer clearEnv()
+
+	if _, err := Load(); err == nil {
+		t.Error("Expected error for invalid registration mode")
+	}
+}
+
 func TestConfig_Validate_EmptyAdvertisedAddress(t *testing.T) {
 	cfg := &Config{
 		App: AppConfig{
@@ -258,6 +409,16 @@ func clearEnv() {
 		"NSDIGUP_PORT",
 		"NSDIGUP_CACHE_MODE",
 		"NSDIGUP_CACHE_TTL",
+		"NSDIGUP_DNS_MODE",
+		"NSDIGUP_DNS_SERVERS",
+		"NSDIGUP_DNS_TIMEOUT",
+		"NSDIGUP_KEEP_ZONE_TRANSFERS",
+		"NSDIGUP_REGISTRATION_MODE",
+		"NSDIGUP_RDAP_BOOTSTRAP_FILE",
+		"NSDIGUP_RDAP_BOOTSTRAP_REFRESH",
+		"NSDIGUP_MMDB_FILES",
+		"NSDIGUP_TAKEOVER_FINGERPRINTS_FILE",
+		"NSDIGUP_PUBLIC_SUFFIX_LIST_FILE",
 	}
 
 	for _, env := range envVars {
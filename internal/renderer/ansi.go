@@ -3,6 +3,8 @@ package renderer
 import (
 	"fmt"
 	"io"
+	"strings"
+	"text/tabwriter"
 	"time"
 
 	"nsdigup/pkg/models"
@@ -25,7 +27,11 @@ func (a *ANSIRenderer) Render(w io.Writer, report *models.Report) error {
 
 	// Header
 	fmt.Fprintf(w, "═══ nsdigup.sh ═══\n")
-	fmt.Fprintf(w, "Target: %s\n", report.Target)
+	if idn := report.Identity.IDN; idn != nil {
+		fmt.Fprintf(w, "Target: %s (%s)\n", idn.Unicode, idn.ASCII)
+	} else {
+		fmt.Fprintf(w, "Target: %s\n", report.Target)
+	}
 	fmt.Fprintf(w, "Scanned: %s\n\n", report.Timestamp.UTC().Format(time.RFC3339))
 
 	// Identity section
@@ -33,6 +39,11 @@ func (a *ANSIRenderer) Render(w io.Writer, report *models.Report) error {
 		return err
 	}
 
+	// DNS records section
+	if err := a.renderRecords(w, report.Records); err != nil {
+		return err
+	}
+
 	// Certificates section
 	if err := a.renderCertificates(w, &report.Certificates); err != nil {
 		return err
@@ -66,26 +77,246 @@ func (a *ANSIRenderer) renderIdentity(w io.Writer, identity *models.Identity) er
 		fmt.Fprintf(w, "  Domain Expires: Unknown\n")
 	}
 
+	if !identity.CreatedAt.IsZero() {
+		created := identity.CreatedAt.Format("2006-01-02")
+		if identity.NewlyRegistered {
+			fmt.Fprintf(w, "  ⚠ Domain Age: %s (registered %s, newly registered)\n", formatAge(identity.AgeDays), created)
+		} else {
+			fmt.Fprintf(w, "  Domain Age: %s (registered %s)\n", formatAge(identity.AgeDays), created)
+		}
+	}
+
+	if !identity.UpdatedAt.IsZero() {
+		updated := identity.UpdatedAt.Format("2006-01-02")
+		if identity.RecentlyUpdated {
+			fmt.Fprintf(w, "  ⚠ Last Updated: %s (registration changed recently)\n", updated)
+		} else {
+			fmt.Fprintf(w, "  Last Updated: %s\n", updated)
+		}
+	}
+
+	// Internationalized domain name
+	if idn := identity.IDN; idn != nil {
+		scripts := strings.Join(idn.Scripts, ", ")
+		switch {
+		case idn.Lookalike != "":
+			fmt.Fprintf(w, "  IDN: ⚠ Possible homograph of %s (%s)\n", idn.Lookalike, scripts)
+		case len(idn.Issues) > 0:
+			fmt.Fprintf(w, "  IDN: ⚠ Possible homograph (%s)\n", scripts)
+		default:
+			fmt.Fprintf(w, "  IDN: ✓ %s (%s)\n", idn.Unicode, scripts)
+		}
+		for _, issue := range idn.Issues {
+			fmt.Fprintf(w, "    ⚠ %s\n", issue)
+		}
+	}
+
+	if identity.RegistrableDomain != "" {
+		fmt.Fprintf(w, "  Registrable Domain: %s\n", identity.RegistrableDomain)
+	}
+
 	if identity.IP != "" {
 		fmt.Fprintf(w, "  IP Address: %s\n", identity.IP)
 	}
 
+	if len(identity.Addresses) > 0 {
+		fmt.Fprintf(w, "  Addresses:\n")
+		for _, addr := range identity.Addresses {
+			fmt.Fprintf(w, "    • %s (%s, TTL %d) 443 %s 80 %s\n", addr.IP, addr.Family, addr.TTL,
+				reachableMark(addr.Reachable443), reachableMark(addr.Reachable80))
+			if addr.Network != nil {
+				fmt.Fprintf(w, "      Network: %s\n", formatNetwork(*addr.Network))
+			}
+			if len(addr.PTR) > 0 {
+				if addr.FCrDNS {
+					fmt.Fprintf(w, "      rDNS: %s (✓ forward-confirmed)\n", strings.Join(addr.PTR, ", "))
+				} else {
+					fmt.Fprintf(w, "      rDNS: %s (⚠ not forward-confirmed)\n", strings.Join(addr.PTR, ", "))
+				}
+			}
+		}
+
+		readiness := identity.IPv6Readiness
+		switch {
+		case readiness.Ready:
+			fmt.Fprintf(w, "  IPv6: ✓ Ready\n")
+		case !readiness.HasAAAA:
+			fmt.Fprintf(w, "  IPv6: ✗ No AAAA records\n")
+		case !readiness.Reachable:
+			fmt.Fprintf(w, "  IPv6: ⚠ AAAA records present but unreachable\n")
+		default:
+			fmt.Fprintf(w, "  IPv6: ⚠ Different certificate served over IPv6\n")
+		}
+	}
+
 	if len(identity.Nameservers) > 0 {
 		fmt.Fprintf(w, "  Nameservers:\n")
 		for _, ns := range identity.Nameservers {
 			fmt.Fprintf(w, "    • %s\n", ns)
+			for _, server := range identity.NameserverAnalysis.Servers {
+				if server.Host != ns {
+					continue
+				}
+				for _, network := range server.Networks {
+					fmt.Fprintf(w, "      Network: %s\n", formatNetwork(network))
+				}
+				if len(server.EDNS) > 0 {
+					fmt.Fprintf(w, "      EDNS:")
+					for _, probe := range server.EDNS {
+						mark := reachableMark(probe.Passed)
+						if probe.Inconclusive {
+							mark = "?"
+						}
+						fmt.Fprintf(w, " %s %s", probe.Test, mark)
+					}
+					fmt.Fprintf(w, "\n")
+				}
+			}
 		}
 	}
 
+	// Nameserver consistency
+	if analysis := identity.NameserverAnalysis; len(analysis.Servers) > 0 {
+		healthy := !analysis.SerialMismatch && len(analysis.LameDelegations) == 0 &&
+			len(analysis.Unreachable) == 0 && len(analysis.OnlyAtParent) == 0 && len(analysis.OnlyAtChild) == 0 &&
+			len(analysis.AXFRAllowed) == 0 && len(analysis.OpenResolvers) == 0 && len(analysis.EDNSFailures) == 0
+		if healthy {
+			fmt.Fprintf(w, "  Nameserver Health: ✓ Consistent\n")
+		} else {
+			fmt.Fprintf(w, "  Nameserver Health: ⚠ Issues Found\n")
+			if analysis.SerialMismatch {
+				fmt.Fprintf(w, "    • SOA serial mismatch:")
+				for _, server := range analysis.Servers {
+					if server.Authoritative {
+						fmt.Fprintf(w, " %s=%d", server.Host, server.Serial)
+					}
+				}
+				fmt.Fprintf(w, "\n")
+			}
+			for _, server := range analysis.Servers {
+				if server.ZoneTransfer.Allowed {
+					fmt.Fprintf(w, "    • Zone transfer (AXFR) allowed: %s (%d records)\n", server.Host, server.ZoneTransfer.RecordCount)
+				}
+			}
+			for _, ns := range analysis.OpenResolvers {
+				fmt.Fprintf(w, "    • Open resolver (answers recursive queries): %s\n", ns)
+			}
+			for _, server := range analysis.Servers {
+				for _, probe := range server.EDNS {
+					if !probe.Passed && !probe.Inconclusive {
+						fmt.Fprintf(w, "    • EDNS %s failed on %s: %s\n", probe.Test, server.Host, probe.Detail)
+					}
+				}
+			}
+			for _, ns := range analysis.LameDelegations {
+				fmt.Fprintf(w, "    • Lame delegation: %s\n", ns)
+			}
+			for _, ns := range analysis.Unreachable {
+				fmt.Fprintf(w, "    • Unreachable: %s\n", ns)
+			}
+			for _, ns := range analysis.OnlyAtParent {
+				fmt.Fprintf(w, "    • Only in parent delegation: %s\n", ns)
+			}
+			for _, ns := range analysis.OnlyAtChild {
+				fmt.Fprintf(w, "    • Only in zone NS set: %s\n", ns)
+			}
+		}
+	}
+
+	// Registration of the nameservers' domains
+	if len(identity.NameserverDomains) > 0 {
+		fmt.Fprintf(w, "  Nameserver Domains:\n")
+		for _, nsDomain := range identity.NameserverDomains {
+			hosts := strings.Join(nsDomain.Nameservers, ", ")
+			switch {
+			case nsDomain.Status == models.StatusUnregistered:
+				fmt.Fprintf(w, "    ✗ %s (%s): unregistered, anyone can register it and take over resolution\n", nsDomain.Domain, hosts)
+			case nsDomain.Status == models.StatusExpired:
+				fmt.Fprintf(w, "    ✗ %s (%s): expired %s\n", nsDomain.Domain, hosts, nsDomain.ExpiresAt.Format("2006-01-02"))
+			case nsDomain.Status == models.StatusExpiringSoon:
+				fmt.Fprintf(w, "    ⚠ %s (%s): expires %s (%d days)\n", nsDomain.Domain, hosts, nsDomain.ExpiresAt.Format("2006-01-02"), nsDomain.ExpiresInDays)
+			case nsDomain.Error != "":
+				fmt.Fprintf(w, "    • %s: registration unknown\n", nsDomain.Domain)
+			case nsDomain.ExpiresAt.IsZero():
+				fmt.Fprintf(w, "    ✓ %s: registered\n", nsDomain.Domain)
+			default:
+				fmt.Fprintf(w, "    ✓ %s: expires %s (%d days)\n", nsDomain.Domain, nsDomain.ExpiresAt.Format("2006-01-02"), nsDomain.ExpiresInDays)
+			}
+		}
+	}
+
+	// Resilience of the nameserver set
+	if diversity := identity.NameserverDiversity; diversity.Nameservers > 0 {
+		mark := "✓"
+		if len(diversity.Issues) > 0 {
+			mark = "⚠"
+		}
+		fmt.Fprintf(w, "  Nameserver Diversity: %s %d/%d (%d servers, %d networks, %d ASNs, %d domains, %d TLDs)\n",
+			mark, diversity.Score, diversity.MaxScore, diversity.Nameservers,
+			len(diversity.IPv4Prefixes)+len(diversity.IPv6Prefixes), len(diversity.ASNs),
+			len(diversity.ParentDomains), len(diversity.TLDs))
+		for _, issue := range diversity.Issues {
+			fmt.Fprintf(w, "    ⚠ %s\n", issue)
+		}
+	}
+
+	// Wildcard records
+	if wildcard := identity.Wildcard; wildcard.Detected {
+		fmt.Fprintf(w, "  Wildcard DNS: ⚠ Detected\n")
+		if wildcard.CNAME != "" {
+			fmt.Fprintf(w, "    • CNAME: %s\n", wildcard.CNAME)
+		}
+		if len(wildcard.A) > 0 {
+			fmt.Fprintf(w, "    • A: %s\n", strings.Join(wildcard.A, ", "))
+		}
+		if len(wildcard.AAAA) > 0 {
+			fmt.Fprintf(w, "    • AAAA: %s\n", strings.Join(wildcard.AAAA, ", "))
+		}
+		if len(wildcard.MX) > 0 {
+			fmt.Fprintf(w, "    • MX: %s\n", strings.Join(wildcard.MX, ", "))
+		}
+		if wildcard.CoveredByCertificate {
+			fmt.Fprintf(w, "    • Covered by the wildcard certificate: any name serves trusted HTTPS\n")
+		}
+	} else if wildcard.Probe != "" && wildcard.Error == "" {
+		fmt.Fprintf(w, "  Wildcard DNS: ✓ None\n")
+	}
+
 	// WHOIS information
 	if identity.Registrar != "" {
 		fmt.Fprintf(w, "  Registrar: %s\n", identity.Registrar)
 	}
 
-	if identity.Owner != "" {
+	if identity.PrivacyService != "" {
+		fmt.Fprintf(w, "  Owner: %s (privacy service: %s)\n", identity.Owner, identity.PrivacyService)
+	} else if identity.Owner != "" {
 		fmt.Fprintf(w, "  Owner: %s\n", identity.Owner)
 	}
 
+	var abuse []string
+	for _, contact := range []string{identity.AbuseEmail, identity.AbusePhone} {
+		if contact != "" {
+			abuse = append(abuse, contact)
+		}
+	}
+	if len(abuse) > 0 {
+		fmt.Fprintf(w, "  Abuse Contact: %s\n", strings.Join(abuse, ", "))
+	}
+
+	// EPP status codes
+	if status := identity.DomainStatus; len(status.Codes) > 0 {
+		fmt.Fprintf(w, "  Domain Status: %s\n", strings.Join(status.Codes, ", "))
+		if status.RegistryLock {
+			fmt.Fprintf(w, "    ✓ Registry lock\n")
+		}
+		for _, issue := range status.Critical {
+			fmt.Fprintf(w, "    ✗ %s\n", issue)
+		}
+		for _, issue := range status.Issues {
+			fmt.Fprintf(w, "    ⚠ %s\n", issue)
+		}
+	}
+
 	// DNSSEC
 	if identity.DNSSECEnabled {
 		if identity.DNSSECValid {
@@ -95,17 +326,94 @@ func (a *ANSIRenderer) renderIdentity(w io.Writer, identity *models.Identity) er
 			if identity.DNSSECError != "" {
 				fmt.Fprintf(w, "    Error: %s\n", identity.DNSSECError)
 			}
+			if len(identity.DNSSECChain) > 0 {
+				fmt.Fprintf(w, "    Chain of Trust:\n")
+				for _, link := range identity.DNSSECChain {
+					if link.Status == models.DNSSECStatusSecure {
+						fmt.Fprintf(w, "      ✓ %s\n", link.Zone)
+					} else {
+						fmt.Fprintf(w, "      ✗ %s (%s): %s\n", link.Zone, link.Status, link.Error)
+					}
+				}
+			}
 		}
 	} else {
 		fmt.Fprintf(w, "  DNSSEC: ✗ Not Enabled\n")
 	}
 
+	// Keys and signatures
+	if signing := identity.DNSSECSigning; signing != nil {
+		fmt.Fprintf(w, "  DNSSEC Keys (%s):\n", signing.Zone)
+		for _, key := range signing.Keys {
+			if key.Bits > 0 {
+				fmt.Fprintf(w, "    • %s %d %s (%d bits)\n", key.Role, key.KeyTag, key.Algorithm, key.Bits)
+			} else {
+				fmt.Fprintf(w, "    • %s %d %s\n", key.Role, key.KeyTag, key.Algorithm)
+			}
+		}
+		for _, ds := range signing.DS {
+			fmt.Fprintf(w, "    • DS %d %s, digest %s\n", ds.KeyTag, ds.Algorithm, ds.DigestType)
+		}
+		for _, sig := range signing.Signatures {
+			fmt.Fprintf(w, "    • RRSIG %s by %d expires %s (%d days)\n", sig.TypeCovered, sig.KeyTag,
+				sig.ExpiresAt.Format("2006-01-02"), sig.ExpiresInDays)
+		}
+		for _, issue := range signing.Issues {
+			fmt.Fprintf(w, "    ⚠ %s\n", issue)
+		}
+
+		rollover := signing.Rollover
+		switch rollover.Status {
+		case "":
+		case models.RolloverInSync:
+			fmt.Fprintf(w, "    Rollover: ✓ CDS/CDNSKEY in sync with parent DS\n")
+		default:
+			fmt.Fprintf(w, "    Rollover: ⚠ %s\n", rollover.Status)
+		}
+		for _, issue := range rollover.Issues {
+			fmt.Fprintf(w, "    ⚠ %s\n", issue)
+		}
+	}
+
+	// Denial of existence
+	if denial := identity.DNSSECDenial; denial != nil {
+		switch denial.Method {
+		case models.DenialNSEC3:
+			salt := denial.Salt
+			if salt == "" {
+				salt = "none"
+			}
+			optOut := "off"
+			if denial.OptOut {
+				optOut = "on"
+			}
+			fmt.Fprintf(w, "  Denial of Existence: NSEC3 (iterations %d, salt %s, opt-out %s)\n",
+				denial.Iterations, salt, optOut)
+		case models.DenialNSEC:
+			if denial.Walkable {
+				fmt.Fprintf(w, "  Denial of Existence: NSEC\n")
+			} else {
+				fmt.Fprintf(w, "  Denial of Existence: NSEC (compact, not walkable)\n")
+			}
+		}
+		for _, issue := range denial.Issues {
+			fmt.Fprintf(w, "    ⚠ %s\n", issue)
+		}
+	}
+
 	// CAA Records
 	if len(identity.CAARecords) > 0 {
 		fmt.Fprintf(w, "  CAA Records:\n")
 		for _, caa := range identity.CAARecords {
 			fmt.Fprintf(w, "    • %s\n", caa)
 		}
+		policy := identity.CAAPolicy
+		for _, violation := range policy.Violations {
+			fmt.Fprintf(w, "    ⚠ %s\n", violation)
+		}
+		if policy.CertificateCA != "" && len(policy.Violations) == 0 {
+			fmt.Fprintf(w, "    ✓ Certificate CA authorized: %s\n", policy.CertificateCA)
+		}
 	} else if identity.CAAMissing {
 		fmt.Fprintf(w, "  CAA Records: ⚠ Missing\n")
 	}
@@ -114,6 +422,27 @@ func (a *ANSIRenderer) renderIdentity(w io.Writer, identity *models.Identity) er
 	return nil
 }
 
+func (a *ANSIRenderer) renderRecords(w io.Writer, records []models.DNSRecord) error {
+	fmt.Fprintf(w, "[ DNS RECORDS ]\n")
+
+	if len(records) == 0 {
+		fmt.Fprintf(w, "  No records found\n\n")
+		return nil
+	}
+
+	// Compact table, one record per line
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, record := range records {
+		fmt.Fprintf(tw, "  %s\t%d\t%s\t%s\n", record.Type, record.TTL, record.Name, record.Value)
+	}
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintf(w, "\n")
+	return nil
+}
+
 func (a *ANSIRenderer) renderCertificates(w io.Writer, certs *models.Certificates) error {
 	fmt.Fprintf(w, "[ CERTIFICATES ]\n")
 
@@ -237,6 +566,19 @@ func (a *ANSIRenderer) renderFindings(w io.Writer, findings *models.Findings) er
 	// Check if we have any Email findings
 	hasEmailFindings = findings.Email.EmailSec.SPF != "" || findings.Email.EmailSec.DMARC != ""
 
+	// Check if the SOA record was fetched
+	hasSOAFindings := findings.SOA.MName != ""
+
+	// Subdomain takeover comes first, it is the most severe finding
+	if findings.Takeover.Vulnerable {
+		fmt.Fprintf(w, "  ✗ Subdomain Takeover [%s]: %s\n", strings.ToUpper(findings.Takeover.Severity), findings.Takeover.Reason)
+		fmt.Fprintf(w, "    CNAME Chain: %s\n", strings.Join(findings.Takeover.CNAMEChain, " → "))
+		if hasHTTPFindings || hasEmailFindings || hasSOAFindings {
+			fmt.Fprintf(w, "\n")
+		}
+		hasIssues = true
+	}
+
 	// HTTP Section
 	if hasHTTPFindings {
 		fmt.Fprintf(w, "  HTTP Posture:\n")
@@ -296,10 +638,70 @@ func (a *ANSIRenderer) renderFindings(w io.Writer, findings *models.Findings) er
 		}
 	}
 
-	if !hasIssues && !hasHTTPFindings && !hasEmailFindings {
+	// Zone hygiene
+	if hasSOAFindings {
+		if hasHTTPFindings || hasEmailFindings {
+			fmt.Fprintf(w, "\n")
+		}
+		fmt.Fprintf(w, "  Zone Hygiene (SOA %s):\n", findings.SOA.Zone)
+		fmt.Fprintf(w, "    Timers: refresh %d, retry %d, expire %d, minimum %d\n",
+			findings.SOA.Refresh, findings.SOA.Retry, findings.SOA.Expire, findings.SOA.Minimum)
+		if len(findings.SOA.Issues) == 0 {
+			fmt.Fprintf(w, "    ✓ Follows RFC 1912 recommendations\n")
+		}
+		for _, issue := range findings.SOA.Issues {
+			fmt.Fprintf(w, "    ⚠ %s\n", issue)
+		}
+		if len(findings.SOA.Issues) > 0 {
+			hasIssues = true
+		}
+	}
+
+	if !hasIssues && !hasHTTPFindings && !hasEmailFindings && !hasSOAFindings {
 		fmt.Fprintf(w, "  ✓ No findings detected\n")
 	}
 
 	fmt.Fprintf(w, "\n")
 	return nil
 }
+
+// formatAge renders a domain age in days, in years once it is over a year old.
+func formatAge(days int) string {
+	switch {
+	case days == 1:
+		return "1 day"
+	case days < 365:
+		return fmt.Sprintf("%d days", days)
+	case days < 730:
+		return "1 year"
+	default:
+		return fmt.Sprintf("%d years", days/365)
+	}
+}
+
+// reachableMark renders a port reachability result.
+func reachableMark(reachable bool) string {
+	if reachable {
+		return "✓"
+	}
+	return "✗"
+}
+
+// formatNetwork renders a network as "AS15169 Google LLC, 142.250.0.0/15, US".
+func formatNetwork(network models.Network) string {
+	var parts []string
+	if network.ASN != 0 {
+		as := fmt.Sprintf("AS%d", network.ASN)
+		if network.Organization != "" {
+			as += " " + network.Organization
+		}
+		parts = append(parts, as)
+	}
+	if network.Prefix != "" {
+		parts = append(parts, network.Prefix)
+	}
+	if network.Country != "" {
+		parts = append(parts, network.Country)
+	}
+	return strings.Join(parts, ", ")
+}
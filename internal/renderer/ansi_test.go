@@ -269,3 +269,742 @@ func TestANSIRenderer_NilReport(t *testing.T) {
 		t.Errorf("Expected nil error message, got: %v", err)
 	}
 }
+
+func TestANSIRenderer_DNSSECChain(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "broken.example",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			DNSSECEnabled: true,
+			DNSSECValid:   false,
+			DNSSECError:   "broken.example.: key mismatch",
+			DNSSECChain: []models.DNSSECZone{
+				{Zone: ".", Status: models.DNSSECStatusSecure},
+				{Zone: "example.", Status: models.DNSSECStatusSecure},
+				{Zone: "broken.example.", Status: models.DNSSECStatusBogus, Error: "key mismatch: no DNSKEY matches the DS records at the parent"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "Chain of Trust:") {
+		t.Error("Expected chain of trust breakdown")
+	}
+
+	if !strings.Contains(output, "✓ example.") {
+		t.Error("Expected secure link for parent zone")
+	}
+
+	if !strings.Contains(output, "✗ broken.example. (bogus): key mismatch") {
+		t.Error("Expected broken link with reason")
+	}
+}
+
+func TestANSIRenderer_NameserverHealth(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "example.com",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			Nameservers: []string{"ns1.example.com", "ns2.example.com", "ns3.example.com"},
+			NameserverAnalysis: models.NameserverAnalysis{
+				Servers: []models.NameserverCheck{
+					{Host: "ns1.example.com", Reachable: true, Authoritative: true, Serial: 2024010101},
+					{Host: "ns2.example.com", Reachable: true, Authoritative: true, Serial: 2024010100,
+						EDNS: []models.EDNSProbe{
+							{Test: "edns", Passed: true},
+							{Test: "tcp", Passed: false, Detail: "connection refused"},
+						}},
+					{Host: "ns3.example.com", Reachable: true},
+					{Host: "ns4.example.com", Reachable: true, Authoritative: true, Serial: 2024010101,
+						ZoneTransfer: models.ZoneTransfer{Allowed: true, RecordCount: 42}},
+				},
+				AXFRAllowed:     []string{"ns4.example.com"},
+				OpenResolvers:   []string{"ns2.example.com"},
+				SerialMismatch:  true,
+				LameDelegations: []string{"ns3.example.com"},
+				OnlyAtParent:    []string{"ns4.example.com"},
+				EDNSFailures:    []string{"ns2.example.com"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "Nameserver Health: ⚠ Issues Found") {
+		t.Error("Expected nameserver health warning")
+	}
+
+	if !strings.Contains(output, "ns1.example.com=2024010101 ns2.example.com=2024010100") {
+		t.Error("Expected serials of each authoritative server")
+	}
+
+	if !strings.Contains(output, "Zone transfer (AXFR) allowed: ns4.example.com (42 records)") {
+		t.Error("Expected open zone transfer to be listed")
+	}
+
+	if !strings.Contains(output, "Open resolver (answers recursive queries): ns2.example.com") {
+		t.Error("Expected open resolver to be listed")
+	}
+
+	if !strings.Contains(output, "EDNS tcp failed on ns2.example.com: connection refused") {
+		t.Error("Expected failed EDNS test to be listed")
+	}
+
+	if !strings.Contains(output, "Lame delegation: ns3.example.com") {
+		t.Error("Expected lame delegation to be listed")
+	}
+
+	if !strings.Contains(output, "Only in parent delegation: ns4.example.com") {
+		t.Error("Expected parent-only nameserver to be listed")
+	}
+}
+
+func TestANSIRenderer_Addresses(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "example.com",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			IP: "192.0.2.10",
+			Addresses: []models.Address{
+				{IP: "192.0.2.10", Family: models.AddressFamilyIPv4, TTL: 300, Reachable443: true, Reachable80: true, PTR: []string{"web.example.com"}, FCrDNS: true,
+					Network: &models.Network{ASN: 64500, Organization: "Example Hosting", Prefix: "192.0.2.0/24", Country: "NL"}},
+				{IP: "2001:db8::10", Family: models.AddressFamilyIPv6, TTL: 300, PTR: []string{"shared.hosting.example"}},
+			},
+			IPv6Readiness: models.IPv6Readiness{HasAAAA: true, SameCertificate: true},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "192.0.2.10 (ipv4, TTL 300) 443 ✓ 80 ✓") {
+		t.Error("Expected reachable IPv4 address")
+	}
+
+	if !strings.Contains(output, "2001:db8::10 (ipv6, TTL 300) 443 ✗ 80 ✗") {
+		t.Error("Expected unreachable IPv6 address")
+	}
+
+	if !strings.Contains(output, "Network: AS64500 Example Hosting, 192.0.2.0/24, NL") {
+		t.Error("Expected hosting network of the address")
+	}
+
+	if !strings.Contains(output, "rDNS: web.example.com (✓ forward-confirmed)") {
+		t.Error("Expected forward-confirmed reverse DNS")
+	}
+
+	if !strings.Contains(output, "rDNS: shared.hosting.example (⚠ not forward-confirmed)") {
+		t.Error("Expected unconfirmed reverse DNS")
+	}
+
+	if !strings.Contains(output, "IPv6: ⚠ AAAA records present but unreachable") {
+		t.Error("Expected IPv6 readiness warning")
+	}
+}
+
+func TestANSIRenderer_SubdomainTakeover(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "shop.example.com",
+		Timestamp: time.Now(),
+		Findings: models.Findings{
+			Takeover: models.TakeoverFinding{
+				CNAMEChain: []string{"example-shop.azurewebsites.net"},
+				Vulnerable: true,
+				Severity:   models.SeverityHigh,
+				Service:    "Microsoft Azure",
+				Reason:     "Microsoft Azure resource example-shop.azurewebsites.net does not exist (NXDOMAIN)",
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "✗ Subdomain Takeover [HIGH]: Microsoft Azure resource") {
+		t.Error("Expected high severity takeover finding")
+	}
+
+	if !strings.Contains(output, "CNAME Chain: example-shop.azurewebsites.net") {
+		t.Error("Expected CNAME chain")
+	}
+
+	if strings.Contains(output, "No findings detected") {
+		t.Error("Expected takeover to count as a finding")
+	}
+}
+
+func TestANSIRenderer_NameserverDomains(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "example.com",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			NameserverDomains: []models.NameserverDomain{
+				{Domain: "dns.example", Nameservers: []string{"ns1.dns.example"}, Registered: true,
+					ExpiresAt: time.Date(2031, 3, 15, 0, 0, 0, 0, time.UTC), ExpiresInDays: 1600, Status: models.StatusActive},
+				{Domain: "lapsed.example", Nameservers: []string{"ns1.lapsed.example", "ns2.lapsed.example"},
+					Status: models.StatusUnregistered},
+				{Domain: "soon.example", Nameservers: []string{"ns.soon.example"}, Registered: true,
+					ExpiresAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC), ExpiresInDays: 12, Status: models.StatusExpiringSoon},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	for _, expected := range []string{
+		"✓ dns.example: expires 2031-03-15 (1600 days)",
+		"✗ lapsed.example (ns1.lapsed.example, ns2.lapsed.example): unregistered",
+		"⚠ soon.example (ns.soon.example): expires 2026-11-01 (12 days)",
+	} {
+		if !strings.Contains(output, expected) {
+			t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+		}
+	}
+}
+
+func TestANSIRenderer_NameserverDiversity(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "example.com",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			NameserverDiversity: models.NameserverDiversity{
+				Nameservers:   2,
+				IPv4Prefixes:  []string{"192.0.2.0/24"},
+				ParentDomains: []string{"example.com"},
+				TLDs:          []string{"com"},
+				Score:         1,
+				MaxScore:      4,
+				Issues:        []string{"All nameserver IPv4 addresses are in a single /24 network: 192.0.2.0/24"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "Nameserver Diversity: ⚠ 1/4 (2 servers, 1 networks, 0 ASNs, 1 domains, 1 TLDs)") {
+		t.Errorf("Expected diversity score, got:\n%s", output)
+	}
+
+	if !strings.Contains(output, "⚠ All nameserver IPv4 addresses are in a single /24 network: 192.0.2.0/24") {
+		t.Error("Expected single point of failure to be listed")
+	}
+}
+
+func TestANSIRenderer_DomainAge(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	tests := []struct {
+		name     string
+		identity models.Identity
+		expected []string
+	}{
+		{
+			name: "newly registered",
+			identity: models.Identity{
+				CreatedAt:       time.Date(2025, 12, 20, 0, 0, 0, 0, time.UTC),
+				UpdatedAt:       time.Date(2025, 12, 21, 0, 0, 0, 0, time.UTC),
+				AgeDays:         12,
+				NewlyRegistered: true,
+				RecentlyUpdated: true,
+			},
+			expected: []string{
+				"⚠ Domain Age: 12 days (registered 2025-12-20, newly registered)",
+				"⚠ Last Updated: 2025-12-21 (registration changed recently)",
+			},
+		},
+		{
+			name: "established",
+			identity: models.Identity{
+				CreatedAt: time.Date(1997, 9, 15, 0, 0, 0, 0, time.UTC),
+				UpdatedAt: time.Date(2024, 8, 2, 0, 0, 0, 0, time.UTC),
+				AgeDays:   10000,
+			},
+			expected: []string{
+				"  Domain Age: 27 years (registered 1997-09-15)",
+				"  Last Updated: 2024-08-02\n",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report := &models.Report{
+				Target:    "example.com",
+				Timestamp: time.Now(),
+				Identity:  tt.identity,
+			}
+
+			var buf bytes.Buffer
+			if err := renderer.Render(&buf, report); err != nil {
+				t.Fatalf("Expected no error, got: %v", err)
+			}
+
+			output := buf.String()
+			for _, expected := range tt.expected {
+				if !strings.Contains(output, expected) {
+					t.Errorf("Expected output to contain %q, got:\n%s", expected, output)
+				}
+			}
+		})
+	}
+}
+
+func TestANSIRenderer_IDN(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "xn--80ak6aa92e.com",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			IDN: &models.IDN{
+				ASCII:     "xn--80ak6aa92e.com",
+				Unicode:   "аррӏе.com",
+				Scripts:   []string{"Latin", "Cyrillic"},
+				Lookalike: "apple.com",
+				Issues:    []string{`Label "аррӏе" looks like "apple"`},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "Target: аррӏе.com (xn--80ak6aa92e.com)") {
+		t.Error("Expected both forms of the target")
+	}
+
+	if !strings.Contains(output, "IDN: ⚠ Possible homograph of apple.com (Latin, Cyrillic)") {
+		t.Error("Expected homograph warning")
+	}
+
+	if !strings.Contains(output, `⚠ Label "аррӏе" looks like "apple"`) {
+		t.Error("Expected confusable label to be listed")
+	}
+}
+
+func TestANSIRenderer_RegistrableDomain(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "www.example.co.uk",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			RegistrableDomain: "example.co.uk",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Registrable Domain: example.co.uk") {
+		t.Error("Expected registrable domain")
+	}
+}
+
+func TestANSIRenderer_RedactedOwner(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "example.com",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			Registrar:      "Example Registrar, Inc.",
+			Owner:          models.OwnerRedacted,
+			PrivacyService: "Withheld for Privacy",
+			AbuseEmail:     "abuse@registrar.example",
+			AbusePhone:     "+1.5555550100",
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "Owner: redacted (privacy service: Withheld for Privacy)") {
+		t.Error("Expected redacted owner with privacy service")
+	}
+
+	if !strings.Contains(output, "Abuse Contact: abuse@registrar.example, +1.5555550100") {
+		t.Error("Expected registrar abuse contact")
+	}
+}
+
+func TestANSIRenderer_DomainStatus(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "example.com",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			Registrar: "Example Registrar",
+			DomainStatus: models.DomainStatus{
+				Codes:    []string{"redemptionPeriod", "serverDeleteProhibited"},
+				Critical: []string{"Domain is in its redemption period and will be deleted unless restored"},
+				Issues:   []string{"Transfer not prohibited (no clientTransferProhibited), the domain can be transferred away"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "Domain Status: redemptionPeriod, serverDeleteProhibited") {
+		t.Error("Expected EPP status codes")
+	}
+
+	if !strings.Contains(output, "✗ Domain is in its redemption period") {
+		t.Error("Expected redemption period to be critical")
+	}
+
+	if !strings.Contains(output, "⚠ Transfer not prohibited") {
+		t.Error("Expected missing transfer protection warning")
+	}
+
+	if strings.Contains(output, "Registry lock") {
+		t.Error("Expected no registry lock")
+	}
+}
+
+func TestANSIRenderer_WildcardDNS(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "example.com",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			Wildcard: models.WildcardDNS{
+				Detected:             true,
+				Probe:                "nsdigup-0123456789abcdef.example.com",
+				A:                    []string{"192.0.2.10"},
+				CNAME:                "lb.example.net",
+				CoveredByCertificate: true,
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "Wildcard DNS: ⚠ Detected") {
+		t.Error("Expected wildcard warning")
+	}
+
+	if !strings.Contains(output, "• CNAME: lb.example.net") || !strings.Contains(output, "• A: 192.0.2.10") {
+		t.Error("Expected wildcard targets")
+	}
+
+	if !strings.Contains(output, "Covered by the wildcard certificate") {
+		t.Error("Expected certificate correlation")
+	}
+
+	report.Identity.Wildcard = models.WildcardDNS{Probe: "nsdigup-0123456789abcdef.example.com"}
+	buf.Reset()
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Wildcard DNS: ✓ None") {
+		t.Error("Expected no wildcard")
+	}
+}
+
+func TestANSIRenderer_CAAPolicy(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "example.com",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			CAARecords: []string{"issue letsencrypt.org", "issuewild ;"},
+			CAAPolicy: models.CAAPolicy{
+				Domain:        "example.com",
+				CertificateCA: "Let's Encrypt",
+				Violations:    []string{"A wildcard certificate is served, but CAA issuewild forbids wildcard issuance"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "⚠ A wildcard certificate is served, but CAA issuewild forbids wildcard issuance") {
+		t.Error("Expected CAA violation")
+	}
+
+	if strings.Contains(output, "Certificate CA authorized") {
+		t.Error("Expected no authorization line when CAA is violated")
+	}
+
+	report.Identity.CAAPolicy.Violations = nil
+	buf.Reset()
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "✓ Certificate CA authorized: Let's Encrypt") {
+		t.Error("Expected authorized certificate CA")
+	}
+}
+
+func TestANSIRenderer_DNSRecords(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "example.com",
+		Timestamp: time.Now(),
+		Records: []models.DNSRecord{
+			{Name: "example.com", Type: "A", TTL: 300, Value: "192.0.2.10"},
+			{Name: "example.com", Type: "MX", TTL: 3600, Value: "10 mail.example.com."},
+			{Name: "_submissions._tcp.example.com", Type: "SRV", TTL: 3600, Value: "0 1 465 mail.example.com."},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "[ DNS RECORDS ]") {
+		t.Error("Expected DNS records section")
+	}
+
+	// Columns are aligned across rows
+	if !strings.Contains(output, "  A    300   example.com                    192.0.2.10\n") {
+		t.Errorf("Expected aligned A record, got:\n%s", output)
+	}
+
+	if !strings.Contains(output, "  SRV  3600  _submissions._tcp.example.com  0 1 465 mail.example.com.\n") {
+		t.Errorf("Expected aligned SRV record, got:\n%s", output)
+	}
+
+	report.Records = nil
+	buf.Reset()
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "[ DNS RECORDS ]\n  No records found") {
+		t.Error("Expected empty DNS records section")
+	}
+}
+
+func TestANSIRenderer_SOAFindings(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "example.com",
+		Timestamp: time.Now(),
+		Findings: models.Findings{
+			SOA: models.SOAFindings{
+				Zone:    "example.com",
+				MName:   "ns1.example.com",
+				Refresh: 300,
+				Retry:   600,
+				Expire:  300,
+				Minimum: 3600,
+				Issues:  []string{"Expire 300 is shorter than refresh 300"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "Zone Hygiene (SOA example.com):") {
+		t.Error("Expected zone hygiene section")
+	}
+
+	if !strings.Contains(output, "Timers: refresh 300, retry 600, expire 300, minimum 3600") {
+		t.Error("Expected SOA timers")
+	}
+
+	if !strings.Contains(output, "⚠ Expire 300 is shorter than refresh 300") {
+		t.Error("Expected SOA issue")
+	}
+
+	report.Findings.SOA.Issues = nil
+	buf.Reset()
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "✓ Follows RFC 1912 recommendations") {
+		t.Error("Expected clean SOA")
+	}
+}
+
+func TestANSIRenderer_DNSSECDenial(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	report := &models.Report{
+		Target:    "example.com",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			DNSSECEnabled: true,
+			DNSSECValid:   true,
+			DNSSECDenial: &models.DNSSECDenial{
+				Method:     models.DenialNSEC3,
+				Iterations: 10,
+				Salt:       "AABBCCDD",
+				Issues:     []string{"NSEC3 iterations 10: RFC 9276 recommends 0"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "Denial of Existence: NSEC3 (iterations 10, salt AABBCCDD, opt-out off)") {
+		t.Error("Expected NSEC3 parameters")
+	}
+
+	if !strings.Contains(output, "⚠ NSEC3 iterations 10: RFC 9276 recommends 0") {
+		t.Error("Expected NSEC3 issue")
+	}
+
+	report.Identity.DNSSECDenial = &models.DNSSECDenial{Method: models.DenialNSEC}
+	buf.Reset()
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !strings.Contains(buf.String(), "Denial of Existence: NSEC (compact, not walkable)") {
+		t.Error("Expected compact NSEC")
+	}
+}
+
+func TestANSIRenderer_DNSSECSigning(t *testing.T) {
+	renderer := NewANSIRenderer()
+
+	expires := time.Now().Add(12 * time.Hour)
+	report := &models.Report{
+		Target:    "example.com",
+		Timestamp: time.Now(),
+		Identity: models.Identity{
+			DNSSECEnabled: true,
+			DNSSECValid:   true,
+			DNSSECSigning: &models.DNSSECSigning{
+				Zone: "example.com",
+				Keys: []models.DNSSECKey{
+					{KeyTag: 2371, Role: models.DNSSECRoleKSK, Algorithm: "ECDSAP256SHA256", Bits: 256},
+				},
+				DS: []models.DNSSECDS{{KeyTag: 2371, Algorithm: "ECDSAP256SHA256", DigestType: "SHA256"}},
+				Signatures: []models.DNSSECSignature{
+					{TypeCovered: "DNSKEY", KeyTag: 2371, ExpiresAt: expires, Status: models.StatusExpiringSoon},
+				},
+				Issues: []string{"RRSIG over DNSKEY by key 2371 expires on " + expires.Format("2006-01-02 15:04")},
+				Rollover: models.DNSSECRollover{
+					Status: models.RolloverPending,
+					Issues: []string{"Key rollover pending: parent DS 2371, zone requests 4711"},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := renderer.Render(&buf, report); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	output := buf.String()
+
+	if !strings.Contains(output, "DNSSEC Keys (example.com):") {
+		t.Error("Expected DNSSEC keys section")
+	}
+
+	if !strings.Contains(output, "• KSK 2371 ECDSAP256SHA256 (256 bits)") {
+		t.Error("Expected KSK")
+	}
+
+	if !strings.Contains(output, "• DS 2371 ECDSAP256SHA256, digest SHA256") {
+		t.Error("Expected DS")
+	}
+
+	if !strings.Contains(output, "• RRSIG DNSKEY by 2371 expires "+expires.Format("2006-01-02")+" (0 days)") {
+		t.Error("Expected signature expiry")
+	}
+
+	if !strings.Contains(output, "⚠ RRSIG over DNSKEY by key 2371 expires on") {
+		t.Error("Expected signature warning")
+	}
+
+	if !strings.Contains(output, "Rollover: ⚠ pending") {
+		t.Error("Expected pending rollover")
+	}
+
+	if !strings.Contains(output, "⚠ Key rollover pending: parent DS 2371, zone requests 4711") {
+		t.Error("Expected rollover details")
+	}
+}
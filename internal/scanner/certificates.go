@@ -10,12 +10,14 @@ import (
 )
 
 type CertificateScanner struct {
-	timeout time.Duration
+	timeout  time.Duration
+	resolver tools.Resolver
 }
 
-func NewCertificateScanner(timeout time.Duration) *CertificateScanner {
+func NewCertificateScanner(timeout time.Duration, resolver tools.Resolver) *CertificateScanner {
 	return &CertificateScanner{
-		timeout: timeout,
+		timeout:  timeout,
+		resolver: resolver,
 	}
 }
 
@@ -29,7 +31,7 @@ func (c *CertificateScanner) ScanCertificates(ctx context.Context, domain string
 
 	// Certificate check
 	go func() {
-		certDetails, err := tools.GetCertDetails(ctx, domain, c.timeout)
+		certDetails, err := tools.GetCertDetails(ctx, c.resolver, domain, c.timeout)
 		if err != nil {
 			errChan <- err
 			return
@@ -39,7 +41,7 @@ func (c *CertificateScanner) ScanCertificates(ctx context.Context, domain string
 
 	// TLS analysis
 	go func() {
-		result := tools.AnalyzeTLS(ctx, domain, c.timeout)
+		result := tools.AnalyzeTLS(ctx, c.resolver, domain, c.timeout)
 		tlsChan <- result
 	}()
 
@@ -5,10 +5,12 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"nsdigup/internal/scanner/tools"
 )
 
 func TestCertificateScanner_ScanCertificates(t *testing.T) {
-	scanner := NewCertificateScanner(10 * time.Second)
+	scanner := NewCertificateScanner(10*time.Second, tools.NewSystemResolver(5*time.Second))
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
 	defer cancel()
 
@@ -92,7 +94,7 @@ func TestCertificateScanner_ScanCertificates(t *testing.T) {
 }
 
 func TestCertificateScanner_WildcardDetection(t *testing.T) {
-	scanner := NewCertificateScanner(10 * time.Second)
+	scanner := NewCertificateScanner(10*time.Second, tools.NewSystemResolver(5*time.Second))
 	ctx := context.Background()
 
 	knownWildcardDomains := []string{}
@@ -111,7 +113,7 @@ func TestCertificateScanner_WildcardDetection(t *testing.T) {
 }
 
 func TestCertificateScanner_CertificateExpiry(t *testing.T) {
-	scanner := NewCertificateScanner(10 * time.Second)
+	scanner := NewCertificateScanner(10*time.Second, tools.NewSystemResolver(5*time.Second))
 	ctx := context.Background()
 
 	certData, err := scanner.ScanCertificates(ctx, "google.com")
@@ -10,12 +10,16 @@ import (
 )
 
 type FindingsScanner struct {
-	timeout time.Duration
+	timeout  time.Duration
+	resolver tools.Resolver
+	takeover *tools.TakeoverChecker
 }
 
-func NewFindingsScanner(timeout time.Duration) *FindingsScanner {
+func NewFindingsScanner(timeout time.Duration, resolver tools.Resolver) *FindingsScanner {
 	return &FindingsScanner{
-		timeout: timeout,
+		timeout:  timeout,
+		resolver: resolver,
+		takeover: tools.NewTakeoverChecker("", timeout),
 	}
 }
 
@@ -23,53 +27,63 @@ func (m *FindingsScanner) ScanFindings(ctx context.Context, domain string) (*mod
 	emailFindings := &models.EmailFindings{}
 	httpFindings := &models.HTTPFindings{}
 
-	errChan := make(chan error, 3)
-	emailDone := make(chan bool, 1)
-	headersDone := make(chan bool, 1)
+	// Every check sends exactly one result, so a failing check can't stand
+	// in for another one that hasn't finished yet
+	emailChan := make(chan error, 1)
+	headersChan := make(chan error, 1)
 	redirectChan := make(chan tools.RedirectResult, 1)
+	takeoverChan := make(chan models.TakeoverFinding, 1)
+	soaChan := make(chan models.SOAFindings, 1)
 
 	go func() {
-		emailSec, err := tools.CheckEmailSecurity(ctx, domain)
-		if err != nil {
-			errChan <- err
-		} else {
+		emailSec, err := tools.CheckEmailSecurity(ctx, m.resolver, domain)
+		if err == nil {
 			emailFindings.EmailSec = emailSec
 		}
-		emailDone <- true
+		emailChan <- err
 	}()
 
 	go func() {
-		headers, err := tools.CheckHttpSecurityHeaders(ctx, domain, m.timeout)
-		if err != nil {
-			errChan <- err
-		} else {
+		headers, err := tools.CheckHttpSecurityHeaders(ctx, m.resolver, domain, m.timeout)
+		if err == nil {
 			httpFindings.Headers = headers
 		}
-		headersDone <- true
+		headersChan <- err
 	}()
 
 	go func() {
-		result := tools.CheckHTTPSRedirect(ctx, domain, m.timeout)
+		result := tools.CheckHTTPSRedirect(ctx, m.resolver, domain, m.timeout)
 		redirectChan <- result
 	}()
 
+	go func() {
+		takeoverChan <- m.takeover.Check(ctx, m.resolver, domain)
+	}()
+
+	go func() {
+		soaChan <- tools.CheckSOA(ctx, m.resolver, domain)
+	}()
+
 	timer := time.NewTimer(m.timeout)
 	defer timer.Stop()
 
 	findings := &models.Findings{HTTP: *httpFindings, Email: *emailFindings}
 
 	var redirectResult tools.RedirectResult
-	for range 3 {
+	for range 5 {
 		select {
 		case <-ctx.Done():
 			return findings, ctx.Err()
 		case <-timer.C:
 			return findings, fmt.Errorf("findings scan timeout")
-		case <-emailDone:
-		case <-headersDone:
+		case <-emailChan:
+		case <-headersChan:
 		case redirect := <-redirectChan:
 			redirectResult = redirect
-		case <-errChan:
+		case takeover := <-takeoverChan:
+			findings.Takeover = takeover
+		case soa := <-soaChan:
+			findings.SOA = soa
 		}
 	}
 
@@ -9,7 +9,7 @@ import (
 )
 
 func TestFindingsScanner_ScanFindings(t *testing.T) {
-	scanner := NewFindingsScanner(10 * time.Second)
+	scanner := NewFindingsScanner(10*time.Second, tools.NewSystemResolver(5*time.Second))
 	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
 	defer cancel()
 
@@ -103,7 +103,7 @@ func TestFindingsScanner_EmailSecurity(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.domain, func(t *testing.T) {
-			emailSec, err := tools.CheckEmailSecurity(ctx, tt.domain)
+			emailSec, err := tools.CheckEmailSecurity(ctx, tools.NewSystemResolver(5*time.Second), tt.domain)
 			if err != nil {
 				t.Errorf("Error checking email security: %v", err)
 				return
@@ -160,7 +160,7 @@ func TestFindingsScanner_Headers(t *testing.T) {
 
 	for _, domain := range domains {
 		t.Run(domain, func(t *testing.T) {
-			headers, err := tools.CheckHttpSecurityHeaders(ctx, domain, 5*time.Second)
+			headers, err := tools.CheckHttpSecurityHeaders(ctx, tools.NewSystemResolver(5*time.Second), domain, 5*time.Second)
 			if err != nil {
 				t.Logf("Could not check headers for %s: %v", domain, err)
 				return
@@ -194,7 +194,7 @@ func TestFindingsScanner_Headers(t *testing.T) {
 }
 
 func TestFindingsScanner_ContextTimeout(t *testing.T) {
-	scanner := NewFindingsScanner(10 * time.Second)
+	scanner := NewFindingsScanner(10*time.Second, tools.NewSystemResolver(5*time.Second))
 	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Nanosecond)
 	defer cancel()
 
@@ -3,6 +3,7 @@ package scanner
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"nsdigup/internal/scanner/tools"
@@ -10,89 +11,151 @@ import (
 )
 
 type IdentityScanner struct {
-	timeout time.Duration
+	timeout  time.Duration
+	resolver tools.Resolver
+	rdap     *tools.RDAPClient
+	networks *tools.NetworkDB
+
+	// Keep the records of open zone transfers, not just their count
+	keepZoneTransfers bool
 }
 
-func NewIdentityScanner(timeout time.Duration) *IdentityScanner {
+func NewIdentityScanner(timeout time.Duration, resolver tools.Resolver) *IdentityScanner {
 	return &IdentityScanner{
-		timeout: timeout,
+		timeout:  timeout,
+		resolver: resolver,
 	}
 }
 
 func (i *IdentityScanner) ScanIdentity(ctx context.Context, domain string) (*models.Identity, error) {
-	identity := &models.Identity{}
+	identity := &models.Identity{
+		RegistrableDomain: tools.RegistrableDomain(domain),
+		IDN:               tools.CheckIDN(domain),
+	}
 
 	// Channels for parallel checks
-	ipsChan := make(chan string, 1)
+	addrsChan := make(chan []models.Address, 1)
 	nsChan := make(chan []string, 1)
+	nsAnalysisChan := make(chan models.NameserverAnalysis, 1)
+	nsDomainsChan := make(chan []models.NameserverDomain, 1)
 	dnssecChan := make(chan tools.DNSSECResult, 1)
 	caaChan := make(chan tools.CAAResult, 1)
+	wildcardChan := make(chan models.WildcardDNS, 1)
 	whoisChan := make(chan tools.WHOISResult, 1)
 	errChan := make(chan error, 2)
 
-	// IP lookup
+	// Checks that follow a first lookup share a deadline taken from scan
+	// start, leaving headroom so they finish before the scan times out
+	checkCtx, cancel := context.WithTimeout(ctx, i.timeout*4/5)
+	defer cancel()
+
+	// Address lookup, followed by reverse DNS and probing each address
 	go func() {
-		ip, err := tools.GetIPAddress(ctx, domain)
+		addresses, err := tools.GetAddresses(ctx, i.resolver, domain)
 		if err != nil {
 			errChan <- err
 			return
 		}
-		ipsChan <- ip
+
+		tools.LookupReverseDNS(checkCtx, i.resolver, addresses)
+		tools.ProbeAddresses(checkCtx, domain, addresses, i.timeout/3)
+		addrsChan <- addresses
 	}()
 
-	// Nameserver lookup
+	// Nameserver lookup, followed by querying each nameserver directly
 	go func() {
-		nameservers, err := tools.GetNameservers(ctx, domain)
+		nameservers, err := tools.GetNameservers(ctx, i.resolver, domain)
 		if err != nil {
 			errChan <- err
+			nsAnalysisChan <- models.NameserverAnalysis{}
+			nsDomainsChan <- nil
 			return
 		}
 		nsChan <- nameservers
+
+		// Registration of the nameservers' own domains, alongside the analysis
+		go func() {
+			nsDomainsChan <- tools.CheckNameserverDomains(checkCtx, i.resolver, i.rdap, domain, nameservers, i.timeout*4/5)
+		}()
+
+		// Zone transfer and EDNS checks set separate fields of each server,
+		// so they run side by side on the same analysis
+		analysis := tools.CheckNameserverConsistency(checkCtx, i.resolver, domain, nameservers)
+		var wg sync.WaitGroup
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			tools.CheckZoneTransfers(checkCtx, domain, &analysis, i.keepZoneTransfers)
+		}()
+		go func() {
+			defer wg.Done()
+			tools.CheckEDNSCompliance(checkCtx, domain, &analysis)
+		}()
+		wg.Wait()
+		nsAnalysisChan <- analysis
 	}()
 
 	// DNSSEC validation
 	go func() {
-		result := tools.CheckDNSSEC(ctx, domain, i.timeout)
+		result := tools.CheckDNSSEC(ctx, i.resolver, domain)
 		dnssecChan <- result
 	}()
 
 	// CAA records
 	go func() {
-		result := tools.CheckCAA(ctx, domain, i.timeout)
+		result := tools.CheckCAA(ctx, i.resolver, domain)
 		caaChan <- result
 	}()
 
+	// Wildcard records
+	go func() {
+		result := tools.CheckWildcard(ctx, i.resolver, domain)
+		wildcardChan <- result
+	}()
+
 	// WHOIS lookup
 	go func() {
-		result := tools.CheckWHOIS(ctx, domain, i.timeout)
+		result := tools.CheckWHOIS(ctx, i.resolver, i.rdap, domain, i.timeout)
 		whoisChan <- result
 	}()
 
 	timer := time.NewTimer(i.timeout)
 	defer timer.Stop()
 
-	var ip string
+	var addresses []models.Address
 	var nameservers []string
+	var nsAnalysis models.NameserverAnalysis
+	var nsDomains []models.NameserverDomain
 	var dnssecResult tools.DNSSECResult
 	var caaResult tools.CAAResult
+	var wildcard models.WildcardDNS
 	var whoisResult tools.WHOISResult
 	errors := []error{}
 
-	// Wait for all 5 checks to complete
-	for range 5 {
+	// Wait for all 8 checks to complete
+wait:
+	for range 8 {
 		select {
 		case <-ctx.Done():
 			return nil, ctx.Err()
 		case <-timer.C:
-			return nil, fmt.Errorf("identity scan timeout")
-		case ipAddr := <-ipsChan:
-			ip = ipAddr
+			// Report what has arrived rather than losing the whole identity
+			errors = append(errors, fmt.Errorf("identity scan timeout"))
+			break wait
+		case addrs := <-addrsChan:
+			addresses = addrs
 		case ns := <-nsChan:
 			nameservers = ns
+		case analysis := <-nsAnalysisChan:
+			nsAnalysis = analysis
+		case domains := <-nsDomainsChan:
+			nsDomains = domains
 		case dnssec := <-dnssecChan:
 			dnssecResult = dnssec
 		case caa := <-caaChan:
 			caaResult = caa
+		case w := <-wildcardChan:
+			wildcard = w
 		case whois := <-whoisChan:
 			whoisResult = whois
 		case err := <-errChan:
@@ -100,25 +163,51 @@ func (i *IdentityScanner) ScanIdentity(ctx context.Context, domain string) (*mod
 		}
 	}
 
-	// Set IP and nameservers
-	identity.IP = ip
+	// Set addresses and nameservers, IPv4 addresses come first
+	if len(addresses) > 0 {
+		identity.IP = addresses[0].IP
+		identity.Addresses = addresses
+		identity.IPv6Readiness = tools.EvaluateIPv6Readiness(addresses)
+	}
 	identity.Nameservers = nameservers
+	identity.NameserverAnalysis = nsAnalysis
+	identity.NameserverDomains = nsDomains
+	identity.Wildcard = wildcard
+
+	// Annotate addresses and nameservers with their hosting network
+	tools.EnrichAddresses(i.networks, identity.Addresses)
+	tools.EnrichNameservers(i.networks, &identity.NameserverAnalysis)
+	identity.NameserverDiversity = tools.EvaluateNameserverDiversity(nameservers, identity.NameserverAnalysis)
 
 	// Process DNSSEC results
 	identity.DNSSECEnabled = dnssecResult.Enabled
 	identity.DNSSECValid = dnssecResult.Valid
 	identity.DNSSECError = dnssecResult.Error
+	identity.DNSSECChain = dnssecResult.Chain
+	identity.DNSSECDenial = dnssecResult.Denial
+	identity.DNSSECSigning = dnssecResult.Signing
 
 	// Process CAA results
 	identity.CAARecords = caaResult.Records
 	identity.CAAMissing = caaResult.Missing
+	identity.CAAPolicy = caaResult.Policy
 
 	// Process WHOIS results
 	if whoisResult.Error == nil {
 		identity.Registrar = whoisResult.Registrar
 		identity.Owner = whoisResult.Owner
+		identity.PrivacyService = whoisResult.PrivacyService
+		identity.AbuseEmail = whoisResult.AbuseEmail
+		identity.AbusePhone = whoisResult.AbusePhone
 		identity.ExpiresAt = whoisResult.ExpiresAt
 		identity.ExpiresInDays = whoisResult.ExpiresInDays
+		identity.CreatedAt = whoisResult.CreatedAt
+		identity.UpdatedAt = whoisResult.UpdatedAt
+		identity.AgeDays = models.CalculateDaysSince(whoisResult.CreatedAt)
+		identity.NewlyRegistered = models.IsNewlyRegistered(whoisResult.CreatedAt)
+		identity.RecentlyUpdated = models.IsRecentlyUpdated(whoisResult.UpdatedAt)
+		identity.RegistrationSource = whoisResult.Source
+		identity.DomainStatus = tools.ParseDomainStatus(whoisResult.Statuses)
 	}
 
 	// Always calculate status (defaults to Active if no expiration data)
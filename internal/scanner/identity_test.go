@@ -5,10 +5,12 @@ import (
 	"strings"
 	"testing"
 	"time"
+
+	"nsdigup/internal/scanner/tools"
 )
 
 func TestIdentityScanner_ScanIdentity(t *testing.T) {
-	scanner := NewIdentityScanner(2 * time.Second)
+	scanner := NewIdentityScanner(2*time.Second, tools.NewSystemResolver(2*time.Second))
 	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Second)
 	defer cancel()
 
@@ -70,7 +72,7 @@ func TestIdentityScanner_ScanIdentity(t *testing.T) {
 }
 
 func TestIdentityScanner_ContextCancellation(t *testing.T) {
-	scanner := NewIdentityScanner(10 * time.Second)
+	scanner := NewIdentityScanner(10*time.Second, tools.NewSystemResolver(10*time.Second))
 	ctx, cancel := context.WithCancel(context.Background())
 	cancel()
 
@@ -0,0 +1,50 @@
+package scanner
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"nsdigup/internal/scanner/tools"
+	"nsdigup/pkg/models"
+)
+
+type RecordsScanner struct {
+	timeout  time.Duration
+	resolver tools.Resolver
+}
+
+func NewRecordsScanner(timeout time.Duration, resolver tools.Resolver) *RecordsScanner {
+	return &RecordsScanner{
+		timeout:  timeout,
+		resolver: resolver,
+	}
+}
+
+func (r *RecordsScanner) ScanRecords(ctx context.Context, domain string) ([]models.DNSRecord, error) {
+	recordsChan := make(chan []models.DNSRecord, 1)
+	errChan := make(chan error, 1)
+
+	go func() {
+		records, err := tools.GetRecords(ctx, r.resolver, domain)
+		if err != nil {
+			errChan <- err
+			return
+		}
+		recordsChan <- records
+	}()
+
+	timer := time.NewTimer(r.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-timer.C:
+		return nil, fmt.Errorf("records scan timeout")
+	case records := <-recordsChan:
+		return records, nil
+	case err := <-errChan:
+		return nil, err
+	}
+}
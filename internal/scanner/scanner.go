@@ -6,7 +6,9 @@ import (
 	"sync"
 	"time"
 
+	"nsdigup/internal/config"
 	"nsdigup/internal/logger"
+	"nsdigup/internal/scanner/tools"
 	"nsdigup/pkg/models"
 )
 
@@ -16,17 +18,44 @@ type Scanner interface {
 
 type ScannerImpl struct {
 	identity    *IdentityScanner
+	records     *RecordsScanner
 	certificate *CertificateScanner
 	findings    *FindingsScanner
 }
 
-func NewScanner() *ScannerImpl {
+func NewScanner(cfg *config.Config) *ScannerImpl {
 	defaultTimeout := 10 * time.Second
+	resolver := tools.NewResolver(cfg.DNS)
+
+	if cfg.DNS.PublicSuffixListFile != "" {
+		tools.LoadPublicSuffixList(cfg.DNS.PublicSuffixListFile)
+	}
+
+	identity := NewIdentityScanner(defaultTimeout, resolver)
+	identity.keepZoneTransfers = cfg.DNS.KeepZoneTransfers
+	if cfg.Registration.Mode != config.RegistrationModeWHOIS {
+		identity.rdap = tools.NewRDAPClient(cfg.Registration.RDAPBootstrapFile, cfg.Registration.RDAPBootstrapRefresh, resolver)
+	}
+
+	if len(cfg.Enrichment.MMDBFiles) > 0 {
+		networks, err := tools.OpenNetworkDB(cfg.Enrichment.MMDBFiles)
+		if err != nil {
+			logger.Get().Warn("network enrichment disabled",
+				slog.String("error", err.Error()))
+		}
+		identity.networks = networks
+	}
+
+	findings := NewFindingsScanner(defaultTimeout, resolver)
+	if cfg.Findings.TakeoverFingerprintsFile != "" {
+		findings.takeover = tools.NewTakeoverChecker(cfg.Findings.TakeoverFingerprintsFile, defaultTimeout)
+	}
 
 	return &ScannerImpl{
-		identity:    NewIdentityScanner(defaultTimeout),
-		certificate: NewCertificateScanner(defaultTimeout),
-		findings:    NewFindingsScanner(defaultTimeout),
+		identity:    identity,
+		records:     NewRecordsScanner(defaultTimeout, resolver),
+		certificate: NewCertificateScanner(defaultTimeout, resolver),
+		findings:    findings,
 	}
 }
 
@@ -43,7 +72,7 @@ func (o *ScannerImpl) Scan(ctx context.Context, domain string) (*models.Report,
 	var mu sync.Mutex
 	errors := make([]error, 0)
 
-	wg.Add(3)
+	wg.Add(4)
 
 	// Identity scan
 	go func() {
@@ -71,6 +100,30 @@ func (o *ScannerImpl) Scan(ctx context.Context, domain string) (*models.Report,
 		mu.Unlock()
 	}()
 
+	// DNS record inventory
+	go func() {
+		defer wg.Done()
+		start := time.Now()
+		records, err := o.records.ScanRecords(ctx, domain)
+		duration := time.Since(start)
+
+		mu.Lock()
+		if err != nil {
+			log.Warn("records scan failed",
+				slog.String("domain", domain),
+				slog.String("error", err.Error()),
+				slog.Duration("duration", duration))
+			errors = append(errors, err)
+		} else {
+			log.Debug("records scan completed",
+				slog.String("domain", domain),
+				slog.Duration("duration", duration),
+				slog.Int("records", len(records)))
+		}
+		report.Records = records
+		mu.Unlock()
+	}()
+
 	// Certificate scan
 	go func() {
 		defer wg.Done()
@@ -124,6 +177,12 @@ func (o *ScannerImpl) Scan(ctx context.Context, domain string) (*models.Report,
 
 	wg.Wait()
 
+	// A wildcard certificate matching wildcard DNS serves any name over trusted HTTPS
+	tools.CorrelateWildcardCertificate(&report.Identity.Wildcard, report.Certificates)
+
+	// Flag a served certificate that CAA would not have allowed
+	tools.CheckCAACertificate(&report.Identity.CAAPolicy, report.Certificates)
+
 	// Check if complete failure (no results from any scanner)
 	if len(errors) > 0 && report.Identity.IP == "" && report.Certificates.CommonName == "" {
 		log.Error("complete scan failure",
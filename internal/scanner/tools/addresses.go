@@ -0,0 +1,189 @@
+package tools
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// Ports probed on every address, overridden in tests to reach local servers
+var (
+	httpsPort = "443"
+	httpPort  = "80"
+)
+
+// GetAddresses retrieves every A and AAAA record of the domain with its TTL,
+// IPv4 addresses first. A failed lookup in one family is only an error when
+// the other family has no addresses either.
+func GetAddresses(ctx context.Context, resolver Resolver, domain string) ([]models.Address, error) {
+	var addresses []models.Address
+	var lastErr error
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		resp, err := lookup(ctx, resolver, domain, qtype)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		for _, ans := range resp.Answer {
+			switch rr := ans.(type) {
+			case *dns.A:
+				addresses = append(addresses, models.Address{IP: rr.A.String(), Family: models.AddressFamilyIPv4, TTL: rr.Hdr.Ttl})
+			case *dns.AAAA:
+				addresses = append(addresses, models.Address{IP: rr.AAAA.String(), Family: models.AddressFamilyIPv6, TTL: rr.Hdr.Ttl})
+			}
+		}
+	}
+
+	if len(addresses) == 0 {
+		if lastErr != nil {
+			return nil, fmt.Errorf("IP lookup failed: %w", lastErr)
+		}
+		return nil, fmt.Errorf("no IP addresses found for domain")
+	}
+
+	return addresses, nil
+}
+
+// ProbeAddresses checks TCP reachability on ports 443 and 80 of every
+// address in parallel, and records the fingerprint of the certificate
+// served for domain on 443.
+func ProbeAddresses(ctx context.Context, domain string, addresses []models.Address, timeout time.Duration) {
+	var wg sync.WaitGroup
+	for i := range addresses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			probeAddress(ctx, domain, &addresses[i], timeout)
+		}()
+	}
+	wg.Wait()
+}
+
+func probeAddress(ctx context.Context, domain string, address *models.Address, timeout time.Duration) {
+	dialer := &net.Dialer{Timeout: timeout}
+	tlsDialer := &tls.Dialer{
+		NetDialer: dialer,
+		Config: &tls.Config{
+			ServerName:         domain,
+			InsecureSkipVerify: true, // Only the identity of the certificate matters here
+		},
+	}
+
+	conn, err := tlsDialer.DialContext(ctx, "tcp", net.JoinHostPort(address.IP, httpsPort))
+	var headerErr tls.RecordHeaderError
+	if err == nil {
+		address.Reachable443 = true
+		if certs := conn.(*tls.Conn).ConnectionState().PeerCertificates; len(certs) > 0 {
+			sum := sha256.Sum256(certs[0].Raw)
+			address.CertFingerprint = hex.EncodeToString(sum[:])
+		}
+		conn.Close()
+	} else if errors.As(err, &headerErr) {
+		// Something answered on 443, it just doesn't speak TLS
+		address.Reachable443 = true
+	}
+
+	plain, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(address.IP, httpPort))
+	if err == nil {
+		address.Reachable80 = true
+		plain.Close()
+	}
+}
+
+// EvaluateIPv6Readiness decides whether the domain works equally well over
+// IPv6: it needs AAAA records, at least one reachable IPv6 address, and the
+// same certificate served over both families.
+func EvaluateIPv6Readiness(addresses []models.Address) models.IPv6Readiness {
+	readiness := models.IPv6Readiness{}
+
+	ipv4Certs := make(map[string]bool)
+	var ipv6Certs []string
+	for _, address := range addresses {
+		switch address.Family {
+		case models.AddressFamilyIPv4:
+			if address.CertFingerprint != "" {
+				ipv4Certs[address.CertFingerprint] = true
+			}
+		case models.AddressFamilyIPv6:
+			readiness.HasAAAA = true
+			if address.Reachable443 || address.Reachable80 {
+				readiness.Reachable = true
+			}
+			if address.CertFingerprint != "" {
+				ipv6Certs = append(ipv6Certs, address.CertFingerprint)
+			}
+		}
+	}
+
+	// Without TLS on either family there is no certificate to disagree on
+	readiness.SameCertificate = len(ipv4Certs) == 0 && len(ipv6Certs) == 0
+	if len(ipv4Certs) > 0 && len(ipv6Certs) > 0 {
+		readiness.SameCertificate = true
+		for _, fingerprint := range ipv6Certs {
+			if !ipv4Certs[fingerprint] {
+				readiness.SameCertificate = false
+			}
+		}
+	}
+
+	readiness.Ready = readiness.HasAAAA && readiness.Reachable && readiness.SameCertificate
+	return readiness
+}
+
+// LookupReverseDNS fills in the PTR names of every address in parallel and
+// checks forward-confirmed reverse DNS: a PTR name must resolve back to the
+// same address.
+func LookupReverseDNS(ctx context.Context, resolver Resolver, addresses []models.Address) {
+	var wg sync.WaitGroup
+	for i := range addresses {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			lookupReverse(ctx, resolver, &addresses[i])
+		}()
+	}
+	wg.Wait()
+}
+
+func lookupReverse(ctx context.Context, resolver Resolver, address *models.Address) {
+	ip := net.ParseIP(address.IP)
+	arpa, err := dns.ReverseAddr(address.IP)
+	if ip == nil || err != nil {
+		return
+	}
+
+	resp, err := lookup(ctx, resolver, arpa, dns.TypePTR)
+	if err != nil {
+		return
+	}
+
+	for _, ans := range resp.Answer {
+		if ptr, ok := ans.(*dns.PTR); ok {
+			address.PTR = append(address.PTR, normalizeHost(ptr.Ptr))
+		}
+	}
+
+	for _, name := range address.PTR {
+		forward, err := lookupAddresses(ctx, resolver, name)
+		if err != nil {
+			continue
+		}
+		for _, candidate := range forward {
+			if ip.Equal(net.ParseIP(candidate)) {
+				address.FCrDNS = true
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package tools
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+func TestGetAddresses(t *testing.T) {
+	addr := startTestDNSServer(t, testZone(t,
+		"example.test. 60 IN AAAA 2001:db8::10",
+		"example.test. 300 IN A 192.0.2.10",
+		"example.test. 300 IN A 192.0.2.11",
+	))
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+
+	addresses, err := GetAddresses(context.Background(), resolver, "example.test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(addresses) != 3 {
+		t.Fatalf("Expected 3 addresses, got %d", len(addresses))
+	}
+
+	if addresses[0].Family != models.AddressFamilyIPv4 || addresses[0].TTL != 300 {
+		t.Errorf("Expected IPv4 address first with TTL 300, got %+v", addresses[0])
+	}
+
+	if addresses[2].IP != "2001:db8::10" || addresses[2].Family != models.AddressFamilyIPv6 || addresses[2].TTL != 60 {
+		t.Errorf("Unexpected IPv6 address: %+v", addresses[2])
+	}
+}
+
+func TestGetAddresses_FamilyFailure(t *testing.T) {
+	zone := testZone(t,
+		"example.test. 300 IN A 192.0.2.10",
+		"example.test. 300 IN AAAA 2001:db8::10",
+	)
+
+	// The server fails every AAAA query, as some broken middleboxes do
+	addr := startTestDNSServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		if r.Question[0].Qtype == dns.TypeAAAA {
+			m := &dns.Msg{}
+			m.SetRcode(r, dns.RcodeServerFailure)
+			w.WriteMsg(m)
+			return
+		}
+		zone(w, r)
+	}))
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+
+	addresses, err := GetAddresses(context.Background(), resolver, "example.test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(addresses) != 1 || addresses[0].IP != "192.0.2.10" {
+		t.Errorf("Expected the A record only, got %+v", addresses)
+	}
+
+	if _, err := GetAddresses(context.Background(), resolver, "missing.example.test"); err == nil {
+		t.Error("Expected error for a name without addresses")
+	}
+}
+
+func TestProbeAddresses(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	// Nothing listens on the plain HTTP port
+	closed, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve port: %v", err)
+	}
+	_, plainPort, _ := net.SplitHostPort(closed.Addr().String())
+	closed.Close()
+
+	_, tlsPort, _ := net.SplitHostPort(server.Listener.Addr().String())
+	defer func(https, http string) { httpsPort, httpPort = https, http }(httpsPort, httpPort)
+	httpsPort, httpPort = tlsPort, plainPort
+
+	addresses := []models.Address{{IP: "127.0.0.1", Family: models.AddressFamilyIPv4}}
+	ProbeAddresses(context.Background(), "example.test", addresses, time.Second)
+
+	if !addresses[0].Reachable443 {
+		t.Error("Expected port 443 to be reachable")
+	}
+	if addresses[0].Reachable80 {
+		t.Error("Expected port 80 to be unreachable")
+	}
+	if len(addresses[0].CertFingerprint) != 64 {
+		t.Errorf("Expected SHA-256 certificate fingerprint, got %q", addresses[0].CertFingerprint)
+	}
+}
+
+func TestEvaluateIPv6Readiness(t *testing.T) {
+	tests := []struct {
+		name      string
+		addresses []models.Address
+		want      models.IPv6Readiness
+	}{
+		{
+			name:      "IPv4 only",
+			addresses: []models.Address{{Family: models.AddressFamilyIPv4, Reachable443: true, CertFingerprint: "aa"}},
+			want:      models.IPv6Readiness{},
+		},
+		{
+			name: "same certificate",
+			addresses: []models.Address{
+				{Family: models.AddressFamilyIPv4, Reachable443: true, CertFingerprint: "aa"},
+				{Family: models.AddressFamilyIPv6, Reachable443: true, CertFingerprint: "aa"},
+			},
+			want: models.IPv6Readiness{HasAAAA: true, Reachable: true, SameCertificate: true, Ready: true},
+		},
+		{
+			name: "different certificate",
+			addresses: []models.Address{
+				{Family: models.AddressFamilyIPv4, Reachable443: true, CertFingerprint: "aa"},
+				{Family: models.AddressFamilyIPv6, Reachable443: true, CertFingerprint: "bb"},
+			},
+			want: models.IPv6Readiness{HasAAAA: true, Reachable: true},
+		},
+		{
+			name: "unreachable IPv6",
+			addresses: []models.Address{
+				{Family: models.AddressFamilyIPv4, Reachable443: true, CertFingerprint: "aa"},
+				{Family: models.AddressFamilyIPv6},
+			},
+			want: models.IPv6Readiness{HasAAAA: true},
+		},
+		{
+			name: "plain HTTP on both families",
+			addresses: []models.Address{
+				{Family: models.AddressFamilyIPv4, Reachable80: true},
+				{Family: models.AddressFamilyIPv6, Reachable80: true},
+			},
+			want: models.IPv6Readiness{HasAAAA: true, Reachable: true, SameCertificate: true, Ready: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EvaluateIPv6Readiness(tt.addresses); got != tt.want {
+				t.Errorf("Expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestLookupReverseDNS(t *testing.T) {
+	addr := startTestDNSServer(t, testZone(t,
+		"10.2.0.192.in-addr.arpa. 300 IN PTR web.example.test.",
+		"11.2.0.192.in-addr.arpa. 300 IN PTR shared.hosting.test.",
+		"web.example.test. 300 IN A 192.0.2.10",
+		"shared.hosting.test. 300 IN A 198.51.100.1",
+		"0.1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2.ip6.arpa. 300 IN PTR web.example.test.",
+		"web.example.test. 300 IN AAAA 2001:db8::10",
+	))
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+
+	addresses := []models.Address{
+		{IP: "192.0.2.10", Family: models.AddressFamilyIPv4},
+		{IP: "192.0.2.11", Family: models.AddressFamilyIPv4},
+		{IP: "192.0.2.12", Family: models.AddressFamilyIPv4},
+		{IP: "2001:db8::10", Family: models.AddressFamilyIPv6},
+	}
+	LookupReverseDNS(context.Background(), resolver, addresses)
+
+	if len(addresses[0].PTR) != 1 || addresses[0].PTR[0] != "web.example.test" || !addresses[0].FCrDNS {
+		t.Errorf("Expected forward-confirmed PTR, got %+v", addresses[0])
+	}
+
+	if len(addresses[1].PTR) != 1 || addresses[1].FCrDNS {
+		t.Errorf("Expected PTR not resolving back to the address, got %+v", addresses[1])
+	}
+
+	if len(addresses[2].PTR) != 0 || addresses[2].FCrDNS {
+		t.Errorf("Expected no PTR, got %+v", addresses[2])
+	}
+
+	if !addresses[3].FCrDNS {
+		t.Errorf("Expected forward-confirmed IPv6 PTR, got %+v", addresses[3])
+	}
+}
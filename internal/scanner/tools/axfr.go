@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// CheckZoneTransfers attempts an AXFR of the domain's zone against every
+// authoritative nameserver in the analysis and records which ones allow it.
+// The transferred records are only kept when keepRecords is set.
+func CheckZoneTransfers(ctx context.Context, domain string, analysis *models.NameserverAnalysis, keepRecords bool) {
+	zone := dns.Fqdn(normalizeDomain(domain))
+
+	var wg sync.WaitGroup
+	for i := range analysis.Servers {
+		server := &analysis.Servers[i]
+		if !server.Authoritative {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for _, ip := range server.Addresses {
+				// Only try the next address when this one could not be reached
+				server.ZoneTransfer = CheckZoneTransfer(ctx, net.JoinHostPort(ip, nameserverPort), zone, keepRecords)
+				if server.ZoneTransfer.Error == "" {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, server := range analysis.Servers {
+		if server.ZoneTransfer.Allowed {
+			analysis.AXFRAllowed = append(analysis.AXFRAllowed, server.Host)
+		}
+	}
+}
+
+// CheckZoneTransfer attempts an AXFR of zone from server (host:port). A
+// refused transfer is the expected, healthy outcome and is not an error.
+func CheckZoneTransfer(ctx context.Context, server, zone string, keepRecords bool) models.ZoneTransfer {
+	result := models.ZoneTransfer{}
+
+	dialer := &net.Dialer{Timeout: authoritativeQueryTimeout}
+	conn, err := dialer.DialContext(ctx, "tcp", server)
+	if err != nil {
+		result.Error = fmt.Sprintf("connection failed: %v", err)
+		return result
+	}
+
+	// Closing the connection unblocks the transfer when the scan is cancelled
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	msg := &dns.Msg{}
+	msg.SetAxfr(dns.Fqdn(zone))
+
+	transfer := &dns.Transfer{
+		Conn:        &dns.Conn{Conn: conn},
+		ReadTimeout: authoritativeQueryTimeout,
+	}
+
+	envelopes, err := transfer.In(msg, server)
+	if err != nil {
+		conn.Close()
+		result.Error = fmt.Sprintf("transfer request failed: %v", err)
+		return result
+	}
+
+	// Drain the channel even after an error so the transfer goroutine exits.
+	// Errors after the opening SOA still mean data was handed out.
+	first, complete := true, true
+	for envelope := range envelopes {
+		if envelope.Error != nil {
+			first, complete = false, false
+			continue
+		}
+		if first {
+			result.Allowed = true
+			first = false
+		}
+
+		result.RecordCount += len(envelope.RR)
+		if keepRecords {
+			for _, rr := range envelope.RR {
+				result.Records = append(result.Records, rr.String())
+			}
+		}
+	}
+
+	// A complete transfer repeats the SOA at the end, don't count it twice
+	if result.Allowed && complete && result.RecordCount > 1 {
+		result.RecordCount--
+		if keepRecords {
+			result.Records = result.Records[:len(result.Records)-1]
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// axfrHandler serves zone transfers of example.test when allow is set and
+// refuses them otherwise.
+func axfrHandler(t *testing.T, allow bool) dns.HandlerFunc {
+	t.Helper()
+
+	var zone []dns.RR
+	for _, record := range []string{
+		"example.test. 300 IN SOA ns1.example.test. hostmaster.example.test. 1 3600 600 86400 300",
+		"example.test. 300 IN NS ns1.example.test.",
+		"www.example.test. 300 IN A 192.0.2.10",
+		"internal.example.test. 300 IN A 10.0.0.5",
+	} {
+		rr, err := dns.NewRR(record)
+		if err != nil {
+			t.Fatalf("Invalid test record %q: %v", record, err)
+		}
+		zone = append(zone, rr)
+	}
+
+	return func(w dns.ResponseWriter, r *dns.Msg) {
+		m := &dns.Msg{}
+		m.SetReply(r)
+		if !allow || r.Question[0].Qtype != dns.TypeAXFR {
+			m.Rcode = dns.RcodeRefused
+			w.WriteMsg(m)
+			return
+		}
+		m.Answer = append(zone, zone[0])
+		w.WriteMsg(m)
+	}
+}
+
+func TestCheckZoneTransfer(t *testing.T) {
+	open := startTestDNSServer(t, axfrHandler(t, true))
+	closed := startTestDNSServer(t, axfrHandler(t, false))
+	ctx := context.Background()
+
+	result := CheckZoneTransfer(ctx, open, "example.test", false)
+	if !result.Allowed {
+		t.Fatalf("Expected transfer to be allowed, got %+v", result)
+	}
+	if result.RecordCount != 4 {
+		t.Errorf("Expected 4 records without the closing SOA, got %d", result.RecordCount)
+	}
+	if len(result.Records) != 0 {
+		t.Error("Expected records not to be kept by default")
+	}
+
+	kept := CheckZoneTransfer(ctx, open, "example.test", true)
+	if len(kept.Records) != 4 {
+		t.Errorf("Expected 4 kept records, got %d", len(kept.Records))
+	}
+
+	refused := CheckZoneTransfer(ctx, closed, "example.test", false)
+	if refused.Allowed || refused.RecordCount != 0 || refused.Error != "" {
+		t.Errorf("Expected refused transfer without error, got %+v", refused)
+	}
+}
+
+func TestCheckZoneTransfers(t *testing.T) {
+	addr := startTestDNSServer(t, axfrHandler(t, true))
+
+	_, port, _ := net.SplitHostPort(addr)
+	defer func(original string) { nameserverPort = original }(nameserverPort)
+	nameserverPort = port
+
+	analysis := models.NameserverAnalysis{
+		Servers: []models.NameserverCheck{
+			{Host: "ns1.example.test", Addresses: []string{"127.0.0.1"}, Reachable: true, Authoritative: true},
+			{Host: "ns2.example.test", Addresses: []string{"127.0.0.1"}, Reachable: true},
+		},
+	}
+	CheckZoneTransfers(context.Background(), "example.test", &analysis, false)
+
+	if len(analysis.AXFRAllowed) != 1 || analysis.AXFRAllowed[0] != "ns1.example.test" {
+		t.Errorf("Expected only the authoritative server to be checked, got %v", analysis.AXFRAllowed)
+	}
+
+	if analysis.Servers[1].ZoneTransfer.Allowed {
+		t.Error("Expected lame server to be skipped")
+	}
+}
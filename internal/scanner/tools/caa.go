@@ -0,0 +1,221 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// bundledCAAIssuers maps certificate issuer names to the identifiers their
+// CA accepts in CAA records.
+//
+//go:embed data/caa_issuers.json
+var bundledCAAIssuers []byte
+
+// caIssuer is one certificate authority of the bundled table.
+type caIssuer struct {
+	CA          string   `json:"ca"`
+	Identifiers []string `json:"identifiers"`
+	Issuers     []string `json:"issuers"`
+}
+
+var caIssuers = func() []caIssuer {
+	var issuers []caIssuer
+	if err := json.Unmarshal(bundledCAAIssuers, &issuers); err != nil {
+		panic(fmt.Sprintf("invalid bundled CAA issuers: %v", err))
+	}
+	return issuers
+}()
+
+// CAAResult contains the results of CAA record checking
+type CAAResult struct {
+	Records []string
+	Policy  models.CAAPolicy
+	Missing bool
+	Error   error
+}
+
+// CheckCAA queries CAA records for a domain, walking up to parent domains if necessary
+func CheckCAA(ctx context.Context, resolver Resolver, domain string) CAAResult {
+	result := CAAResult{
+		Records: []string{},
+		Missing: false,
+	}
+
+	// Try the domain and walk up to parent domains
+	currentDomain := normalizeDomain(domain)
+	for {
+		records, err := queryCAARecords(ctx, resolver, currentDomain)
+		if err != nil {
+			result.Error = err
+			return result
+		}
+
+		if len(records) > 0 {
+			for _, caa := range records {
+				// Format: "tag value" (e.g., "issue letsencrypt.org")
+				result.Records = append(result.Records, fmt.Sprintf("%s %s", caa.Tag, caa.Value))
+			}
+			result.Policy = parseCAAPolicy(currentDomain, records)
+			result.Missing = false
+			return result
+		}
+
+		// Walk up to parent domain
+		parent := getParentDomain(currentDomain)
+		if parent == "" || parent == currentDomain {
+			// Reached the top-level domain without finding CAA records
+			break
+		}
+		currentDomain = parent
+	}
+
+	// No CAA records found at any level
+	result.Missing = true
+	return result
+}
+
+// queryCAARecords queries CAA records for a specific domain
+func queryCAARecords(ctx context.Context, resolver Resolver, domain string) ([]*dns.CAA, error) {
+	resp, err := query(ctx, resolver, domain, dns.TypeCAA)
+	if err != nil {
+		return nil, fmt.Errorf("CAA query failed: %w", err)
+	}
+
+	if resp == nil || resp.Rcode != dns.RcodeSuccess {
+		return nil, nil // No error, just no records
+	}
+
+	var caaRecords []*dns.CAA
+	for _, ans := range resp.Answer {
+		if caa, ok := ans.(*dns.CAA); ok {
+			caaRecords = append(caaRecords, caa)
+		}
+	}
+
+	return caaRecords, nil
+}
+
+// parseCAAPolicy sorts the CAA records found at domain by tag (RFC 8659).
+func parseCAAPolicy(domain string, records []*dns.CAA) models.CAAPolicy {
+	policy := models.CAAPolicy{Domain: domain}
+
+	for _, caa := range records {
+		switch strings.ToLower(caa.Tag) {
+		case "issue":
+			policy.Issue = append(policy.Issue, parseCAAIssuer(caa))
+		case "issuewild":
+			policy.IssueWild = append(policy.IssueWild, parseCAAIssuer(caa))
+		case "iodef":
+			policy.IODEF = append(policy.IODEF, caa.Value)
+		}
+	}
+
+	return policy
+}
+
+// parseCAAIssuer splits an issue or issuewild value such as
+// "letsencrypt.org; accounturi=https://...; validationmethods=dns-01".
+// An empty issuer domain forbids issuance.
+func parseCAAIssuer(caa *dns.CAA) models.CAAIssuer {
+	parts := strings.Split(caa.Value, ";")
+	issuer := models.CAAIssuer{
+		Domain:   strings.ToLower(strings.TrimSpace(parts[0])),
+		Critical: caa.Flag&128 != 0,
+	}
+
+	for _, param := range parts[1:] {
+		key, value, ok := strings.Cut(strings.TrimSpace(param), "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		if issuer.Parameters == nil {
+			issuer.Parameters = map[string]string{}
+		}
+		issuer.Parameters[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+
+	return issuer
+}
+
+// CheckCAACertificate cross-checks the CA of the served certificate with
+// the CAA policy, flagging certificates that CAA would not have allowed.
+// Certificates from CAs missing in the bundled table are not judged.
+func CheckCAACertificate(policy *models.CAAPolicy, certs models.Certificates) {
+	if policy.Domain == "" || certs.Issuer == "" {
+		return
+	}
+
+	ca := identifyCA(certs.Issuer)
+	if ca == nil {
+		return
+	}
+	policy.CertificateCA = ca.CA
+
+	if len(policy.Issue) > 0 && !caaAuthorizes(policy.Issue, ca) {
+		if caaForbidsAll(policy.Issue) {
+			policy.Violations = append(policy.Violations,
+				fmt.Sprintf("CAA forbids all issuance, but a %s certificate is served", ca.CA))
+		} else {
+			policy.Violations = append(policy.Violations,
+				fmt.Sprintf("%s is not authorized by CAA issue records", ca.CA))
+		}
+	}
+
+	if !certs.IsWildcard {
+		return
+	}
+
+	// issuewild takes precedence over issue for wildcard certificates
+	wildcard := policy.IssueWild
+	if len(wildcard) == 0 {
+		return
+	}
+	if caaForbidsAll(wildcard) {
+		policy.Violations = append(policy.Violations,
+			"A wildcard certificate is served, but CAA issuewild forbids wildcard issuance")
+	} else if !caaAuthorizes(wildcard, ca) {
+		policy.Violations = append(policy.Violations,
+			fmt.Sprintf("%s is not authorized by CAA issuewild records", ca.CA))
+	}
+}
+
+// identifyCA returns the CA whose issuer names the certificate issuer
+// equals or starts with.
+func identifyCA(issuer string) *caIssuer {
+	issuer = strings.ToLower(strings.TrimSpace(issuer))
+	for i := range caIssuers {
+		for _, name := range caIssuers[i].Issuers {
+			name = strings.ToLower(name)
+			if issuer == name || strings.HasPrefix(issuer, name+" ") {
+				return &caIssuers[i]
+			}
+		}
+	}
+	return nil
+}
+
+func caaAuthorizes(issuers []models.CAAIssuer, ca *caIssuer) bool {
+	for _, issuer := range issuers {
+		if slices.Contains(ca.Identifiers, issuer.Domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func caaForbidsAll(issuers []models.CAAIssuer) bool {
+	for _, issuer := range issuers {
+		if issuer.Domain != "" {
+			return false
+		}
+	}
+	return true
+}
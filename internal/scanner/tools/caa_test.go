@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"nsdigup/pkg/models"
+)
+
+func TestCheckCAA(t *testing.T) {
+	addr := startTestDNSServer(t, testZone(t,
+		`example.test. 300 IN CAA 0 issue "letsencrypt.org; accounturi=https://acme-v02.api.letsencrypt.org/acme/acct/1; validationmethods=dns-01"`,
+		`example.test. 300 IN CAA 0 issuewild ";"`,
+		`example.test. 300 IN CAA 128 iodef "mailto:security@example.test"`,
+		"www.example.test. 300 IN A 192.0.2.10",
+	))
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+	result := CheckCAA(context.Background(), resolver, "www.example.test")
+
+	if result.Error != nil {
+		t.Fatalf("Unexpected error: %v", result.Error)
+	}
+	if result.Missing || len(result.Records) != 3 {
+		t.Fatalf("Expected 3 records, got %v", result.Records)
+	}
+
+	policy := result.Policy
+	if policy.Domain != "example.test" {
+		t.Errorf("Expected policy from parent example.test, got %q", policy.Domain)
+	}
+
+	if len(policy.Issue) != 1 || policy.Issue[0].Domain != "letsencrypt.org" {
+		t.Fatalf("Expected issue letsencrypt.org, got %+v", policy.Issue)
+	}
+	if got := policy.Issue[0].Parameters["validationmethods"]; got != "dns-01" {
+		t.Errorf("Expected validationmethods dns-01, got %q", got)
+	}
+	if got := policy.Issue[0].Parameters["accounturi"]; got != "https://acme-v02.api.letsencrypt.org/acme/acct/1" {
+		t.Errorf("Expected accounturi, got %q", got)
+	}
+
+	if len(policy.IssueWild) != 1 || policy.IssueWild[0].Domain != "" {
+		t.Errorf("Expected issuewild forbidding issuance, got %+v", policy.IssueWild)
+	}
+
+	if !slices.Equal(policy.IODEF, []string{"mailto:security@example.test"}) {
+		t.Errorf("Expected iodef mailto, got %v", policy.IODEF)
+	}
+}
+
+func TestIdentifyCA(t *testing.T) {
+	tests := []struct {
+		issuer   string
+		expected string
+	}{
+		{issuer: "R11", expected: "Let's Encrypt"},
+		{issuer: "E6", expected: "Let's Encrypt"},
+		{issuer: "WR2", expected: "Google Trust Services"},
+		{issuer: "GTS CA 1C3", expected: "Google Trust Services"},
+		{issuer: "DigiCert Global G2 TLS RSA SHA256 2020 CA1", expected: "DigiCert"},
+		{issuer: "Sectigo RSA Domain Validation Secure Server CA", expected: "Sectigo"},
+		{issuer: "Amazon RSA 2048 M02", expected: "Amazon"},
+		{issuer: "R1X", expected: ""},
+		{issuer: "Example Internal CA", expected: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.issuer, func(t *testing.T) {
+			ca := identifyCA(tt.issuer)
+			got := ""
+			if ca != nil {
+				got = ca.CA
+			}
+			if got != tt.expected {
+				t.Errorf("Expected %q, got %q", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestCheckCAACertificate(t *testing.T) {
+	letsEncrypt := []models.CAAIssuer{{Domain: "letsencrypt.org"}}
+	forbid := []models.CAAIssuer{{Domain: ""}}
+
+	tests := []struct {
+		name       string
+		policy     models.CAAPolicy
+		certs      models.Certificates
+		ca         string
+		violations int
+	}{
+		{
+			name:   "authorized",
+			policy: models.CAAPolicy{Domain: "example.com", Issue: letsEncrypt},
+			certs:  models.Certificates{Issuer: "R11"},
+			ca:     "Let's Encrypt",
+		},
+		{
+			name:       "unauthorized CA",
+			policy:     models.CAAPolicy{Domain: "example.com", Issue: letsEncrypt},
+			certs:      models.Certificates{Issuer: "DigiCert Global G2 TLS RSA SHA256 2020 CA1"},
+			ca:         "DigiCert",
+			violations: 1,
+		},
+		{
+			name:       "issuance forbidden",
+			policy:     models.CAAPolicy{Domain: "example.com", Issue: forbid},
+			certs:      models.Certificates{Issuer: "R11"},
+			ca:         "Let's Encrypt",
+			violations: 1,
+		},
+		{
+			name:       "wildcard forbidden by issuewild",
+			policy:     models.CAAPolicy{Domain: "example.com", Issue: letsEncrypt, IssueWild: forbid},
+			certs:      models.Certificates{Issuer: "R11", IsWildcard: true},
+			ca:         "Let's Encrypt",
+			violations: 1,
+		},
+		{
+			name: "wildcard CA not in issuewild",
+			policy: models.CAAPolicy{Domain: "example.com",
+				Issue: []models.CAAIssuer{{Domain: "letsencrypt.org"}, {Domain: "pki.goog"}}, IssueWild: letsEncrypt},
+			certs:      models.Certificates{Issuer: "WR2", IsWildcard: true},
+			ca:         "Google Trust Services",
+			violations: 1,
+		},
+		{
+			name:   "only iodef",
+			policy: models.CAAPolicy{Domain: "example.com", IODEF: []string{"mailto:security@example.com"}},
+			certs:  models.Certificates{Issuer: "R11", IsWildcard: true},
+			ca:     "Let's Encrypt",
+		},
+		{
+			name:   "unknown CA",
+			policy: models.CAAPolicy{Domain: "example.com", Issue: letsEncrypt},
+			certs:  models.Certificates{Issuer: "Example Internal CA"},
+		},
+		{
+			name:  "no CAA records",
+			certs: models.Certificates{Issuer: "R11"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy := tt.policy
+			CheckCAACertificate(&policy, tt.certs)
+
+			if policy.CertificateCA != tt.ca {
+				t.Errorf("Expected CA %q, got %q", tt.ca, policy.CertificateCA)
+			}
+			if len(policy.Violations) != tt.violations {
+				t.Errorf("Expected %d violations, got %v", tt.violations, policy.Violations)
+			}
+		})
+	}
+}
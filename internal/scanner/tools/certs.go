@@ -38,18 +38,14 @@ type CertInfo struct {
 // GetCertDetails retrieves and analyzes the TLS certificate for the given domain.
 // It connects to the domain on port 443 and extracts certificate information including
 // issuer, common name, expiration, wildcard status, and overall status.
-func GetCertDetails(ctx context.Context, domain string, timeout time.Duration) (CertInfo, error) {
+func GetCertDetails(ctx context.Context, resolver Resolver, domain string, timeout time.Duration) (CertInfo, error) {
 	// Detect if connecting via IP address
 	isIP := isIPAddress(domain)
 
-	dialer := &net.Dialer{
-		Timeout: timeout,
-	}
-
-	conn, err := tls.DialWithDialer(dialer, "tcp", fmt.Sprintf("%s:443", domain), &tls.Config{
+	conn, err := dialTLS(ctx, resolver, net.JoinHostPort(domain, "443"), &tls.Config{
 		ServerName:         domain,
 		InsecureSkipVerify: true, // Allow connection to inspect expired certs
-	})
+	}, timeout)
 	if err != nil {
 		return CertInfo{}, fmt.Errorf("TLS connection failed: %w", err)
 	}
@@ -125,7 +121,7 @@ func GetCertDetails(ctx context.Context, domain string, timeout time.Duration) (
 	if len(state.PeerCertificates) > 1 {
 		// We need the issuer certificate to create OCSP request
 		issuerCert := state.PeerCertificates[1]
-		isRevoked = checkOCSPRevocation(ctx, cert, issuerCert, timeout)
+		isRevoked = checkOCSPRevocation(ctx, resolver, cert, issuerCert, timeout)
 	} else {
 		logger.GetFromContext(ctx, logger.Get()).Debug("no issuer certificate available for OCSP check",
 			slog.String("domain", domain))
@@ -233,7 +229,7 @@ func validateHostname(domain string, cert *x509.Certificate) bool {
 // checkOCSPRevocation checks if a certificate has been revoked using OCSP.
 // It returns true if the certificate is revoked, false otherwise.
 // Errors during OCSP checking are logged but not treated as revocation.
-func checkOCSPRevocation(ctx context.Context, cert, issuer *x509.Certificate, timeout time.Duration) bool {
+func checkOCSPRevocation(ctx context.Context, resolver Resolver, cert, issuer *x509.Certificate, timeout time.Duration) bool {
 	// Check if certificate has OCSP server URLs
 	if len(cert.OCSPServer) == 0 {
 		logger.GetFromContext(ctx, logger.Get()).Debug("no OCSP servers found in certificate")
@@ -248,10 +244,14 @@ func checkOCSPRevocation(ctx context.Context, cert, issuer *x509.Certificate, ti
 		return false
 	}
 
+	transport := newHTTPTransport(resolver, timeout)
+	defer transport.CloseIdleConnections()
+
 	// Try each OCSP server
 	for _, server := range cert.OCSPServer {
 		httpClient := &http.Client{
-			Timeout: timeout,
+			Timeout:   timeout,
+			Transport: transport,
 		}
 
 		httpRequest, err := http.NewRequest("POST", server, bytes.NewReader(ocspRequest))
@@ -0,0 +1,101 @@
+package tools
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"time"
+)
+
+// dialFunc connects to a host:port address, like net.Dialer.DialContext.
+type dialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// newDialer returns a dialFunc that resolves host names through resolver
+// rather than the operating system, so that HTTP, TLS, RDAP and WHOIS
+// connections reach the same addresses the DNS checks see. The addresses
+// are tried in turn, IPv4 first. IP literals are dialed directly, and a nil
+// resolver leaves resolution to the system.
+func newDialer(resolver Resolver, timeout time.Duration) dialFunc {
+	dialer := &net.Dialer{Timeout: timeout}
+	if resolver == nil {
+		return dialer.DialContext
+	}
+
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		if net.ParseIP(host) != nil {
+			return dialer.DialContext(ctx, network, address)
+		}
+
+		addresses, err := GetAddresses(ctx, resolver, host)
+		if err != nil {
+			return nil, fmt.Errorf("dial %s: %w", address, err)
+		}
+
+		var lastErr error
+		for _, addr := range addresses {
+			conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(addr.IP, port))
+			if err == nil {
+				return conn, nil
+			}
+			lastErr = err
+			if ctx.Err() != nil {
+				break
+			}
+		}
+		return nil, lastErr
+	}
+}
+
+// dialTLS connects to address through resolver and completes a TLS
+// handshake within timeout. The server name defaults to the host of
+// address, as it does for tls.Dial.
+func dialTLS(ctx context.Context, resolver Resolver, address string, config *tls.Config, timeout time.Duration) (*tls.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	if config.ServerName == "" {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		config = config.Clone()
+		config.ServerName = host
+	}
+
+	raw, err := newDialer(resolver, timeout)(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+
+	conn := tls.Client(raw, config)
+	if err := conn.HandshakeContext(ctx); err != nil {
+		raw.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// newHTTPTransport returns an HTTP transport that resolves host names
+// through resolver. The URL host is still used for the Host header and SNI.
+func newHTTPTransport(resolver Resolver, timeout time.Duration) *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = newDialer(resolver, timeout)
+	return transport
+}
+
+// whoisDialer adapts a dialFunc to the context-free dialer the WHOIS client
+// takes.
+type whoisDialer struct {
+	ctx  context.Context
+	dial dialFunc
+}
+
+func (d whoisDialer) Dial(network, address string) (net.Conn, error) {
+	return d.dial(d.ctx, network, address)
+}
@@ -0,0 +1,69 @@
+package tools
+
+import (
+	"context"
+	"crypto/tls"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPTransport_UsesResolver(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.WriteString(w, r.Host)
+	}))
+	defer server.Close()
+
+	// Only the test resolver knows the name, the system resolver doesn't
+	addr := startTestDNSServer(t, testZone(t, "web.example.test. 300 IN A 127.0.0.1"))
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	client := &http.Client{Transport: newHTTPTransport(resolver, time.Second)}
+
+	resp, err := client.Get("http://web.example.test:" + port + "/")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(resp.Body)
+	if string(body) != "web.example.test:"+port {
+		t.Errorf("Expected Host header web.example.test:%s, got %q", port, body)
+	}
+
+	if _, err := client.Get("http://missing.example.test:" + port + "/"); err == nil {
+		t.Error("Expected error for a name the resolver doesn't know")
+	}
+}
+
+func TestDialTLS_UsesResolver(t *testing.T) {
+	serverName := make(chan string, 1)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.TLS = &tls.Config{
+		GetConfigForClient: func(hello *tls.ClientHelloInfo) (*tls.Config, error) {
+			serverName <- hello.ServerName
+			return nil, nil
+		},
+	}
+	server.StartTLS()
+	defer server.Close()
+
+	addr := startTestDNSServer(t, testZone(t, "tls.example.test. 300 IN A 127.0.0.1"))
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+	conn, err := dialTLS(context.Background(), resolver, net.JoinHostPort("tls.example.test", port),
+		&tls.Config{InsecureSkipVerify: true}, time.Second)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	conn.Close()
+
+	if sni := <-serverName; sni != "tls.example.test" {
+		t.Errorf("Expected SNI tls.example.test, got %q", sni)
+	}
+}
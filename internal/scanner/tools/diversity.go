@@ -0,0 +1,122 @@
+package tools
+
+import (
+	"fmt"
+	"net"
+	"slices"
+	"strings"
+
+	"nsdigup/pkg/models"
+)
+
+// diversityAxes is the number of axes a nameserver set is scored on: server
+// count, networks, ASNs, parent domains and TLDs.
+const diversityAxes = 5
+
+// EvaluateNameserverDiversity scores how well the nameservers survive the
+// loss of a single network, provider, domain or TLD (RFC 2182 section 3.1).
+// Addresses and networks come from the nameserver analysis; ASNs are only
+// known when network enrichment is enabled, and the ASN axis is left out of
+// MaxScore otherwise.
+func EvaluateNameserverDiversity(nameservers []string, analysis models.NameserverAnalysis) models.NameserverDiversity {
+	diversity := models.NameserverDiversity{
+		Nameservers: len(nameservers),
+		MaxScore:    diversityAxes,
+	}
+	if len(nameservers) == 0 {
+		return diversity
+	}
+
+	for _, ns := range nameservers {
+		host := strings.TrimSuffix(strings.ToLower(ns), ".")
+		if parent := RegistrableDomain(host); parent != "" {
+			diversity.ParentDomains = appendUnique(diversity.ParentDomains, parent)
+		}
+		diversity.TLDs = appendUnique(diversity.TLDs, host[strings.LastIndex(host, ".")+1:])
+
+		for _, server := range analysis.Servers {
+			if server.Host != ns {
+				continue
+			}
+			for _, ip := range server.Addresses {
+				if prefix, ok := addressPrefix(ip); ok {
+					if strings.Contains(prefix, ":") {
+						diversity.IPv6Prefixes = appendUnique(diversity.IPv6Prefixes, prefix)
+					} else {
+						diversity.IPv4Prefixes = appendUnique(diversity.IPv4Prefixes, prefix)
+					}
+				}
+			}
+			for _, network := range server.Networks {
+				if network.ASN != 0 && !slices.Contains(diversity.ASNs, network.ASN) {
+					diversity.ASNs = append(diversity.ASNs, network.ASN)
+				}
+			}
+		}
+	}
+
+	if len(nameservers) >= 2 {
+		diversity.Score++
+	} else {
+		diversity.Issues = append(diversity.Issues,
+			"Only one nameserver, at least two are required (RFC 1034 section 4.1)")
+	}
+
+	// Each address family has to be diverse on its own, since resolvers
+	// reaching the servers over only one family depend on it alone
+	singleIPv4 := len(diversity.IPv4Prefixes) == 1
+	singleIPv6 := len(diversity.IPv6Prefixes) == 1
+	if singleIPv4 {
+		diversity.Issues = append(diversity.Issues,
+			fmt.Sprintf("All nameserver IPv4 addresses are in a single /24 network: %s", diversity.IPv4Prefixes[0]))
+	}
+	if singleIPv6 {
+		diversity.Issues = append(diversity.Issues,
+			fmt.Sprintf("All nameserver IPv6 addresses are in a single /48 network: %s", diversity.IPv6Prefixes[0]))
+	}
+	if len(diversity.IPv4Prefixes)+len(diversity.IPv6Prefixes) > 0 && !singleIPv4 && !singleIPv6 {
+		diversity.Score++
+	}
+
+	// Without network enrichment the ASN axis can't be scored either way
+	switch {
+	case len(diversity.ASNs) >= 2:
+		diversity.Score++
+	case len(diversity.ASNs) == 1:
+		diversity.Issues = append(diversity.Issues,
+			fmt.Sprintf("All nameservers are in a single network operator: AS%d", diversity.ASNs[0]))
+	default:
+		diversity.MaxScore--
+	}
+
+	if len(diversity.ParentDomains) >= 2 {
+		diversity.Score++
+	} else if len(diversity.ParentDomains) == 1 {
+		diversity.Issues = append(diversity.Issues,
+			fmt.Sprintf("All nameservers are under one domain: %s", diversity.ParentDomains[0]))
+	}
+
+	if len(diversity.TLDs) >= 2 {
+		diversity.Score++
+	} else {
+		diversity.Issues = append(diversity.Issues,
+			fmt.Sprintf("All nameservers are under one TLD: .%s", diversity.TLDs[0]))
+	}
+
+	return diversity
+}
+
+// addressPrefix returns the /24 of an IPv4 address or the /48 of an IPv6
+// address, the usual granularity of routing and anycast announcements.
+func addressPrefix(ip string) (string, bool) {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return "", false
+	}
+	if v4 := parsed.To4(); v4 != nil {
+		network := &net.IPNet{IP: v4.Mask(net.CIDRMask(24, 32)), Mask: net.CIDRMask(24, 32)}
+		return network.String(), true
+	}
+	network := &net.IPNet{IP: parsed.Mask(net.CIDRMask(48, 128)), Mask: net.CIDRMask(48, 128)}
+	return network.String(), true
+}
@@ -0,0 +1,129 @@
+package tools
+
+import (
+	"testing"
+
+	"nsdigup/pkg/models"
+)
+
+func TestEvaluateNameserverDiversity(t *testing.T) {
+	tests := []struct {
+		name        string
+		nameservers []string
+		servers     []models.NameserverCheck
+		score       int
+		maxScore    int
+		issues      int
+	}{
+		{
+			name:        "no nameservers",
+			nameservers: nil,
+			score:       0,
+			maxScore:    diversityAxes,
+			issues:      0,
+		},
+		{
+			name:        "single nameserver",
+			nameservers: []string{"ns1.example.com"},
+			servers: []models.NameserverCheck{
+				{Host: "ns1.example.com", Addresses: []string{"192.0.2.1"}},
+			},
+			score:    0,
+			maxScore: diversityAxes - 1,
+			issues:   4,
+		},
+		{
+			name:        "same /24 and domain",
+			nameservers: []string{"ns1.example.com", "ns2.example.com"},
+			servers: []models.NameserverCheck{
+				{Host: "ns1.example.com", Addresses: []string{"192.0.2.1"}, Networks: []models.Network{{ASN: 64500}}},
+				{Host: "ns2.example.com", Addresses: []string{"192.0.2.2"}, Networks: []models.Network{{ASN: 64500}}},
+			},
+			score:    1,
+			maxScore: diversityAxes,
+			issues:   4,
+		},
+		{
+			name:        "fully diverse",
+			nameservers: []string{"ns1.example.com", "ns2.example.net", "a.dns.example.org"},
+			servers: []models.NameserverCheck{
+				{Host: "ns1.example.com", Addresses: []string{"192.0.2.1", "2001:db8:1::1"}, Networks: []models.Network{{ASN: 64500}}},
+				{Host: "ns2.example.net", Addresses: []string{"198.51.100.1", "2001:db8:2::1"}, Networks: []models.Network{{ASN: 64501}}},
+				{Host: "a.dns.example.org", Addresses: []string{"203.0.113.1"}},
+			},
+			score:    diversityAxes,
+			maxScore: diversityAxes,
+			issues:   0,
+		},
+		{
+			name:        "dual-stack in a single /24 and /48",
+			nameservers: []string{"ns1.example.com", "ns2.example.net"},
+			servers: []models.NameserverCheck{
+				{Host: "ns1.example.com", Addresses: []string{"192.0.2.1", "2001:db8:1::1"}},
+				{Host: "ns2.example.net", Addresses: []string{"192.0.2.2", "2001:db8:1::2"}},
+			},
+			score:    3,
+			maxScore: diversityAxes - 1,
+			issues:   2,
+		},
+		{
+			name:        "IPv4 diverse, IPv6 in a single /48",
+			nameservers: []string{"ns1.example.com", "ns2.example.net"},
+			servers: []models.NameserverCheck{
+				{Host: "ns1.example.com", Addresses: []string{"192.0.2.1", "2001:db8:1::1"}},
+				{Host: "ns2.example.net", Addresses: []string{"198.51.100.1", "2001:db8:1::2"}},
+			},
+			score:    3,
+			maxScore: diversityAxes - 1,
+			issues:   1,
+		},
+		{
+			name:        "without network enrichment",
+			nameservers: []string{"ns1.example.com", "ns2.example.net"},
+			servers: []models.NameserverCheck{
+				{Host: "ns1.example.com", Addresses: []string{"2001:db8:1::1"}},
+				{Host: "ns2.example.net", Addresses: []string{"2001:db8:1:ff::1"}},
+			},
+			score:    3,
+			maxScore: diversityAxes - 1,
+			issues:   1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			diversity := EvaluateNameserverDiversity(tt.nameservers, models.NameserverAnalysis{Servers: tt.servers})
+
+			if diversity.Score != tt.score {
+				t.Errorf("Expected score %d, got %d (%+v)", tt.score, diversity.Score, diversity)
+			}
+			if diversity.MaxScore != tt.maxScore {
+				t.Errorf("Expected max score %d, got %d", tt.maxScore, diversity.MaxScore)
+			}
+			if len(diversity.Issues) != tt.issues {
+				t.Errorf("Expected %d issues, got %d: %v", tt.issues, len(diversity.Issues), diversity.Issues)
+			}
+		})
+	}
+}
+
+func TestAddressPrefix(t *testing.T) {
+	tests := []struct {
+		ip       string
+		expected string
+	}{
+		{"192.0.2.77", "192.0.2.0/24"},
+		{"2001:db8:1:ff::1", "2001:db8:1::/48"},
+	}
+
+	for _, tt := range tests {
+		prefix, ok := addressPrefix(tt.ip)
+		if !ok || prefix != tt.expected {
+			t.Errorf("Expected %s for %s, got %s", tt.expected, tt.ip, prefix)
+		}
+	}
+
+	if _, ok := addressPrefix("not-an-ip"); ok {
+		t.Error("Expected invalid address to be rejected")
+	}
+}
@@ -3,61 +3,80 @@ package tools
 import (
 	"context"
 	"fmt"
-	"net"
 	"strings"
-)
 
-// GetIPAddress retrieves the primary IP address for the given domain.
-// It performs a DNS lookup and returns the first IPv4 address found,
-// or the first IP address if no IPv4 address is available.
-func GetIPAddress(ctx context.Context, domain string) (string, error) {
-	resolver := &net.Resolver{}
+	"github.com/miekg/dns"
+)
 
-	ips, err := resolver.LookupIPAddr(ctx, domain)
+// GetNameservers retrieves the nameserver records for the given domain.
+// It performs a DNS NS lookup and returns a list of nameserver hostnames
+// with trailing dots removed.
+func GetNameservers(ctx context.Context, resolver Resolver, domain string) ([]string, error) {
+	resp, err := lookup(ctx, resolver, domain, dns.TypeNS)
 	if err != nil {
-		return "", fmt.Errorf("IP lookup failed: %w", err)
+		return nil, fmt.Errorf("NS lookup failed: %w", err)
 	}
 
-	if len(ips) == 0 {
-		return "", fmt.Errorf("no IP addresses found for domain")
+	nameservers := []string{}
+	for _, ans := range resp.Answer {
+		if ns, ok := ans.(*dns.NS); ok {
+			nsHost := strings.TrimSuffix(ns.Ns, ".")
+			nameservers = append(nameservers, nsHost)
+		}
 	}
 
-	var ipList []net.IP
-	for _, ip := range ips {
-		ipList = append(ipList, ip.IP)
+	return nameservers, nil
+}
+
+// lookupTXT returns the TXT records for name, each record's strings joined
+// together as net.LookupTXT does.
+func lookupTXT(ctx context.Context, resolver Resolver, name string) ([]string, error) {
+	resp, err := lookup(ctx, resolver, name, dns.TypeTXT)
+	if err != nil {
+		return nil, err
 	}
 
-	// Prefer IPv4 addresses
-	for _, ip := range ipList {
-		if ip.To4() != nil {
-			return ip.String(), nil
+	var records []string
+	for _, ans := range resp.Answer {
+		if txt, ok := ans.(*dns.TXT); ok {
+			records = append(records, strings.Join(txt.Txt, ""))
 		}
 	}
-
-	// Fallback to first IP if no IPv4 found
-	return ipList[0].String(), nil
+	return records, nil
 }
 
-// GetNameservers retrieves the nameserver records for the given domain.
-// It performs a DNS NS lookup and returns a list of nameserver hostnames
-// with trailing dots removed.
-func GetNameservers(ctx context.Context, domain string) ([]string, error) {
-	resolver := &net.Resolver{}
-
-	ns, err := resolver.LookupNS(ctx, domain)
+// lookup sends a recursive query and treats NXDOMAIN as an error, mirroring
+// the behaviour of the standard library resolver.
+func lookup(ctx context.Context, resolver Resolver, name string, qtype uint16) (*dns.Msg, error) {
+	resp, err := query(ctx, resolver, name, qtype)
 	if err != nil {
-		return nil, fmt.Errorf("NS lookup failed: %w", err)
+		return nil, err
 	}
 
-	if len(ns) == 0 {
-		return []string{}, nil
+	switch resp.Rcode {
+	case dns.RcodeSuccess:
+		return resp, nil
+	case dns.RcodeNameError:
+		return nil, fmt.Errorf("no such host: %s", name)
+	default:
+		return nil, fmt.Errorf("%s lookup for %s returned %s",
+			dns.TypeToString[qtype], name, dns.RcodeToString[resp.Rcode])
 	}
+}
 
-	nameservers := make([]string, 0, len(ns))
-	for _, n := range ns {
-		nsHost := strings.TrimSuffix(n.Host, ".")
-		nameservers = append(nameservers, nsHost)
-	}
+// query sends a single recursive query through the resolver.
+func query(ctx context.Context, resolver Resolver, name string, qtype uint16) (*dns.Msg, error) {
+	msg := &dns.Msg{}
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = true
+	msg.SetEdns0(4096, false)
 
-	return nameservers, nil
+	resp, err := resolver.Exchange(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+	if resp == nil {
+		return nil, fmt.Errorf("no response received")
+	}
+	return resp, nil
 }
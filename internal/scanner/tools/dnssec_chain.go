@@ -0,0 +1,407 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// rootTrustAnchors are the DS records of the IANA root zone KSKs
+// (KSK-2017 and KSK-2024), published at https://data.iana.org/root-anchors/.
+var rootTrustAnchors = mustParseDS(
+	". IN DS 20326 8 2 E06D44B80B8F1D39A95C0B0D7C65D08458E880409BBC683457104237C7F8EC8D",
+	". IN DS 38696 8 2 683D2D0ACB8C9B712A1948B27F741219298D0A450D612C483AF444A4C0FB2B16",
+)
+
+// walkChainOfTrust validates every zone cut between the root and the domain.
+// Each zone's DNSKEY RRset must be signed by a key matching the DS RRset
+// held by its parent, and that DS RRset must itself be signed by the
+// parent's keys. The returned chain records where validation broke.
+func walkChainOfTrust(ctx context.Context, resolver Resolver, domain string) ([]models.DNSSECZone, error) {
+	var chain []models.DNSSECZone
+	var trustedKeys []*dns.DNSKEY
+
+	// Status and zone of the first link that failed to validate
+	broken, brokenZone := "", ""
+
+	for _, zone := range zoneCandidates(domain) {
+		link := models.DNSSECZone{Zone: zone}
+
+		var ds []*dns.DS
+		if zone == "." {
+			ds = rootTrustAnchors
+		} else {
+			resp, err := exchangeDNSSEC(ctx, resolver, zone, dns.TypeDS)
+			if err != nil {
+				return chain, err
+			}
+			dsRRs, dsSigs := answerRRset(resp, zone, dns.TypeDS)
+
+			if len(dsRRs) == 0 {
+				// No DS: either an insecure delegation or not a zone cut at all
+				apex, err := isZoneApex(ctx, resolver, zone)
+				if err != nil {
+					return chain, err
+				}
+				if !apex {
+					continue
+				}
+
+				// A signed parent has to prove the DS RRset doesn't exist,
+				// otherwise it may have been stripped on the way
+				if broken == "" && len(chain) > 0 {
+					if err := proveNoDS(resp, zone, trustedKeys); err != nil {
+						link.Status = models.DNSSECStatusBogus
+						link.Error = fmt.Sprintf("missing DS at parent is not proven: %v", err)
+					}
+				}
+			} else {
+				ds = toDS(dsRRs)
+				if broken == "" {
+					if err := verifyRRset(dsRRs, dsSigs, trustedKeys); err != nil {
+						link.Status = models.DNSSECStatusBogus
+						link.Error = fmt.Sprintf("DS RRset at parent: %v", err)
+					}
+				}
+			}
+		}
+
+		for _, d := range ds {
+			link.DSKeyTags = append(link.DSKeyTags, d.KeyTag)
+		}
+
+		keyRRs, keySigs, err := queryRRset(ctx, resolver, zone, dns.TypeDNSKEY)
+		if err != nil {
+			return chain, err
+		}
+		keys := toDNSKEY(keyRRs)
+		for _, k := range keys {
+			link.KeyTags = append(link.KeyTags, k.KeyTag())
+		}
+
+		switch {
+		case broken != "":
+			link.Status = broken
+			link.Error = fmt.Sprintf("parent zone %s is %s", brokenZone, broken)
+		case link.Status == models.DNSSECStatusBogus:
+			// DS RRset or its denial failed verification above
+		case len(ds) == 0 && len(keys) == 0:
+			link.Status = models.DNSSECStatusInsecure
+			link.Error = "zone is not signed"
+		case len(ds) == 0:
+			link.Status = models.DNSSECStatusInsecure
+			link.Error = "missing DS at parent: zone publishes DNSKEY but the delegation is unsigned"
+		case len(keys) == 0:
+			link.Status = models.DNSSECStatusBogus
+			link.Error = "parent has DS records but zone publishes no DNSKEY"
+		default:
+			matched := matchDS(ds, keys)
+			if len(matched) == 0 {
+				link.Status = models.DNSSECStatusBogus
+				link.Error = "key mismatch: no DNSKEY matches the DS records at the parent"
+			} else if err := verifyRRset(keyRRs, keySigs, matched); err != nil {
+				link.Status = models.DNSSECStatusBogus
+				link.Error = fmt.Sprintf("DNSKEY RRset: %v", err)
+			} else {
+				link.Status = models.DNSSECStatusSecure
+				trustedKeys = keys
+			}
+		}
+
+		if link.Status != models.DNSSECStatusSecure && broken == "" {
+			broken, brokenZone = link.Status, zone
+		}
+
+		chain = append(chain, link)
+	}
+
+	// Finally check the domain's own data is signed by the zone it lives in
+	if broken == "" && len(chain) > 0 {
+		rrs, sigs, err := queryRRset(ctx, resolver, dns.Fqdn(domain), dns.TypeA)
+		if err != nil {
+			return chain, err
+		}
+		if len(rrs) > 0 {
+			if err := verifyRRset(rrs, sigs, trustedKeys); err != nil {
+				last := &chain[len(chain)-1]
+				last.Status = models.DNSSECStatusBogus
+				last.Error = fmt.Sprintf("A RRset for %s: %v", domain, err)
+			}
+		}
+	}
+
+	return chain, nil
+}
+
+// verifyRRset checks that at least one RRSIG over rrset was made by one of
+// the given keys and is inside its validity period.
+func verifyRRset(rrset []dns.RR, sigs []*dns.RRSIG, keys []*dns.DNSKEY) error {
+	if len(sigs) == 0 {
+		return fmt.Errorf("no RRSIG records found")
+	}
+
+	var lastErr error
+	now := time.Now()
+	for _, sig := range sigs {
+		for _, key := range keys {
+			if key.KeyTag() != sig.KeyTag || key.Algorithm != sig.Algorithm ||
+				!strings.EqualFold(key.Hdr.Name, sig.SignerName) {
+				continue
+			}
+
+			if !sig.ValidityPeriod(now) {
+				lastErr = fmt.Errorf("signature by key %d is outside its validity period (%s to %s)",
+					sig.KeyTag,
+					dns.TimeToString(sig.Inception),
+					dns.TimeToString(sig.Expiration))
+				continue
+			}
+
+			if err := sig.Verify(key, rrset); err != nil {
+				if lastErr == nil {
+					lastErr = fmt.Errorf("signature by key %d does not verify: %v", sig.KeyTag, err)
+				}
+				continue
+			}
+
+			return nil
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no RRSIG made by a trusted key")
+	}
+	return lastErr
+}
+
+// matchDS returns the keys that hash to one of the DS records.
+func matchDS(ds []*dns.DS, keys []*dns.DNSKEY) []*dns.DNSKEY {
+	var matched []*dns.DNSKEY
+	for _, key := range keys {
+		for _, d := range ds {
+			if key.KeyTag() != d.KeyTag || key.Algorithm != d.Algorithm {
+				continue
+			}
+			computed := key.ToDS(d.DigestType)
+			if computed != nil && strings.EqualFold(computed.Digest, d.Digest) {
+				matched = append(matched, key)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+// proveNoDS checks the NSEC or NSEC3 records a signed parent returned with
+// an empty DS answer for zone (RFC 4035 section 5.2, RFC 5155 section 8.9).
+// They must be signed by the parent's keys and show a delegation without DS,
+// or, with NSEC3 opt-out, a delegation that isn't covered by NSEC3 at all.
+func proveNoDS(resp *dns.Msg, zone string, keys []*dns.DNSKEY) error {
+	var sigs []*dns.RRSIG
+	for _, rr := range resp.Ns {
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			sigs = append(sigs, sig)
+		}
+	}
+	verify := func(rr dns.RR) error {
+		var covering []*dns.RRSIG
+		for _, sig := range sigs {
+			if sig.TypeCovered == rr.Header().Rrtype && strings.EqualFold(sig.Hdr.Name, rr.Header().Name) {
+				covering = append(covering, sig)
+			}
+		}
+		if err := verifyRRset([]dns.RR{rr}, covering, keys); err != nil {
+			return fmt.Errorf("%s %s: %w", dns.TypeToString[rr.Header().Rrtype], rr.Header().Name, err)
+		}
+		return nil
+	}
+
+	var nsec3s []*dns.NSEC3
+	for _, rr := range resp.Ns {
+		switch denial := rr.(type) {
+		case *dns.NSEC:
+			if !strings.EqualFold(denial.Hdr.Name, zone) {
+				continue
+			}
+			if err := verify(denial); err != nil {
+				return err
+			}
+			return checkDelegationBitmap(denial.TypeBitMap)
+		case *dns.NSEC3:
+			nsec3s = append(nsec3s, denial)
+		}
+	}
+
+	if len(nsec3s) == 0 {
+		return fmt.Errorf("no NSEC or NSEC3 records in the response")
+	}
+
+	for _, nsec3 := range nsec3s {
+		if nsec3.Match(zone) {
+			if err := verify(nsec3); err != nil {
+				return err
+			}
+			return checkDelegationBitmap(nsec3.TypeBitMap)
+		}
+	}
+
+	// No NSEC3 for the delegation itself: its closest encloser has to match
+	// and the next closer name has to fall in an opt-out span
+	labels := dns.SplitDomainName(zone)
+	for i := 1; i < len(labels); i++ {
+		encloser := dns.Fqdn(strings.Join(labels[i:], "."))
+		nextCloser := dns.Fqdn(strings.Join(labels[i-1:], "."))
+
+		var match, cover *dns.NSEC3
+		for _, nsec3 := range nsec3s {
+			if nsec3.Match(encloser) {
+				match = nsec3
+			}
+			if nsec3.Cover(nextCloser) {
+				cover = nsec3
+			}
+		}
+		if match == nil {
+			continue
+		}
+		if cover == nil {
+			return fmt.Errorf("no NSEC3 covers %s", nextCloser)
+		}
+		for _, nsec3 := range []*dns.NSEC3{match, cover} {
+			if err := verify(nsec3); err != nil {
+				return err
+			}
+		}
+		if cover.Flags&1 == 0 {
+			return fmt.Errorf("NSEC3 covering %s is not opt-out", nextCloser)
+		}
+		return nil
+	}
+
+	return fmt.Errorf("no NSEC3 matches %s or an enclosing name", zone)
+}
+
+// checkDelegationBitmap accepts the type bitmap of an unsigned delegation:
+// NS present, DS and SOA absent.
+func checkDelegationBitmap(types []uint16) error {
+	switch {
+	case slices.Contains(types, dns.TypeDS):
+		return fmt.Errorf("denial record lists a DS RRset")
+	case !slices.Contains(types, dns.TypeNS) || slices.Contains(types, dns.TypeSOA):
+		return fmt.Errorf("denial record is not for a delegation")
+	}
+	return nil
+}
+
+// queryRRset fetches an RRset and the RRSIGs covering it.
+func queryRRset(ctx context.Context, resolver Resolver, name string, qtype uint16) ([]dns.RR, []*dns.RRSIG, error) {
+	resp, err := exchangeDNSSEC(ctx, resolver, name, qtype)
+	if err != nil {
+		return nil, nil, err
+	}
+	rrs, sigs := answerRRset(resp, name, qtype)
+	return rrs, sigs, nil
+}
+
+// exchangeDNSSEC sends a query with the DO bit. The CD bit is set so that
+// data failing validation at the resolver is still returned to us, the
+// resolver only acts as a transport and all signatures are verified locally.
+// NXDOMAIN is returned as an empty response.
+func exchangeDNSSEC(ctx context.Context, resolver Resolver, name string, qtype uint16) (*dns.Msg, error) {
+	msg := &dns.Msg{}
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.SetEdns0(4096, true)
+	msg.CheckingDisabled = true
+
+	resp, err := resolver.Exchange(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("%s query for %s failed: %w", dns.TypeToString[qtype], name, err)
+	}
+
+	if resp.Rcode != dns.RcodeSuccess && resp.Rcode != dns.RcodeNameError {
+		return nil, fmt.Errorf("%s query for %s returned %s",
+			dns.TypeToString[qtype], name, dns.RcodeToString[resp.Rcode])
+	}
+	return resp, nil
+}
+
+// answerRRset extracts the RRset of the given name and type from the answer
+// section, and the RRSIGs covering it.
+func answerRRset(resp *dns.Msg, name string, qtype uint16) ([]dns.RR, []*dns.RRSIG) {
+	var rrs []dns.RR
+	var sigs []*dns.RRSIG
+	for _, ans := range resp.Answer {
+		if !strings.EqualFold(ans.Header().Name, dns.Fqdn(name)) {
+			continue
+		}
+		if sig, ok := ans.(*dns.RRSIG); ok {
+			if sig.TypeCovered == qtype {
+				sigs = append(sigs, sig)
+			}
+			continue
+		}
+		if ans.Header().Rrtype == qtype {
+			rrs = append(rrs, ans)
+		}
+	}
+
+	return rrs, sigs
+}
+
+// isZoneApex reports whether name is the apex of a zone, i.e. owns an SOA record.
+func isZoneApex(ctx context.Context, resolver Resolver, name string) (bool, error) {
+	rrs, _, err := queryRRset(ctx, resolver, name, dns.TypeSOA)
+	if err != nil {
+		return false, err
+	}
+	return len(rrs) > 0, nil
+}
+
+// zoneCandidates lists the root and every ancestor of domain, top down
+// (e.g. "www.example.com" -> ".", "com.", "example.com.", "www.example.com.").
+func zoneCandidates(domain string) []string {
+	labels := dns.SplitDomainName(domain)
+	candidates := []string{"."}
+	for i := len(labels) - 1; i >= 0; i-- {
+		candidates = append(candidates, dns.Fqdn(strings.Join(labels[i:], ".")))
+	}
+	return candidates
+}
+
+func toDS(rrs []dns.RR) []*dns.DS {
+	ds := make([]*dns.DS, 0, len(rrs))
+	for _, rr := range rrs {
+		if d, ok := rr.(*dns.DS); ok {
+			ds = append(ds, d)
+		}
+	}
+	return ds
+}
+
+func toDNSKEY(rrs []dns.RR) []*dns.DNSKEY {
+	keys := make([]*dns.DNSKEY, 0, len(rrs))
+	for _, rr := range rrs {
+		if k, ok := rr.(*dns.DNSKEY); ok {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func mustParseDS(records ...string) []*dns.DS {
+	ds := make([]*dns.DS, 0, len(records))
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		if err != nil {
+			panic(fmt.Sprintf("invalid trust anchor %q: %v", record, err))
+		}
+		ds = append(ds, rr.(*dns.DS))
+	}
+	return ds
+}
@@ -0,0 +1,375 @@
+package tools
+
+import (
+	"context"
+	"crypto"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// newTestKey generates a zone signing key for the given zone.
+func newTestKey(t *testing.T, zone string, flags uint16) (*dns.DNSKEY, crypto.Signer) {
+	t.Helper()
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: dns.Fqdn(zone), Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     flags,
+		Protocol:  3,
+		Algorithm: dns.ECDSAP256SHA256,
+	}
+	priv, err := key.Generate(256)
+	if err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return key, priv.(crypto.Signer)
+}
+
+// signRRset signs rrset with key over the given validity window.
+func signRRset(t *testing.T, key *dns.DNSKEY, priv crypto.Signer, rrset []dns.RR, inception, expiration time.Time) *dns.RRSIG {
+	t.Helper()
+	sig := &dns.RRSIG{
+		Hdr:        dns.RR_Header{Name: rrset[0].Header().Name, Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 3600},
+		Algorithm:  key.Algorithm,
+		KeyTag:     key.KeyTag(),
+		SignerName: key.Hdr.Name,
+		Inception:  uint32(inception.Unix()),
+		Expiration: uint32(expiration.Unix()),
+	}
+	if err := sig.Sign(priv, rrset); err != nil {
+		t.Fatalf("Failed to sign RRset: %v", err)
+	}
+	return sig
+}
+
+func TestVerifyRRset(t *testing.T) {
+	key, priv := newTestKey(t, "example.com", 257)
+	otherKey, _ := newTestKey(t, "example.com", 257)
+
+	a, _ := dns.NewRR("example.com. 300 IN A 192.0.2.1")
+	rrset := []dns.RR{a}
+	now := time.Now()
+
+	valid := signRRset(t, key, priv, rrset, now.Add(-time.Hour), now.Add(24*time.Hour))
+	expired := signRRset(t, key, priv, rrset, now.Add(-48*time.Hour), now.Add(-24*time.Hour))
+
+	tests := []struct {
+		name    string
+		sigs    []*dns.RRSIG
+		keys    []*dns.DNSKEY
+		wantErr string
+	}{
+		{name: "valid signature", sigs: []*dns.RRSIG{valid}, keys: []*dns.DNSKEY{key}},
+		{name: "no signatures", sigs: nil, keys: []*dns.DNSKEY{key}, wantErr: "no RRSIG records"},
+		{name: "expired signature", sigs: []*dns.RRSIG{expired}, keys: []*dns.DNSKEY{key}, wantErr: "validity period"},
+		{name: "untrusted key", sigs: []*dns.RRSIG{valid}, keys: []*dns.DNSKEY{otherKey}, wantErr: "trusted key"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := verifyRRset(rrset, tt.sigs, tt.keys)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Expected valid RRset, got: %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Expected error containing '%s', got: %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+func TestMatchDS(t *testing.T) {
+	key, _ := newTestKey(t, "example.com", 257)
+	otherKey, _ := newTestKey(t, "example.com", 257)
+
+	ds := []*dns.DS{key.ToDS(dns.SHA256)}
+
+	if matched := matchDS(ds, []*dns.DNSKEY{otherKey, key}); len(matched) != 1 || matched[0] != key {
+		t.Errorf("Expected only the matching key, got %d keys", len(matched))
+	}
+
+	if matched := matchDS(ds, []*dns.DNSKEY{otherKey}); len(matched) != 0 {
+		t.Errorf("Expected no matching keys, got %d", len(matched))
+	}
+}
+
+func TestZoneCandidates(t *testing.T) {
+	got := zoneCandidates("www.example.com")
+	want := []string{".", "com.", "example.com.", "www.example.com."}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Expected %v, got %v", want, got)
+	}
+}
+
+func TestRootTrustAnchors(t *testing.T) {
+	if len(rootTrustAnchors) != 2 {
+		t.Fatalf("Expected 2 root trust anchors, got %d", len(rootTrustAnchors))
+	}
+	for _, ds := range rootTrustAnchors {
+		if ds.Hdr.Name != "." || ds.DigestType != dns.SHA256 {
+			t.Errorf("Unexpected trust anchor: %s", ds.String())
+		}
+	}
+}
+
+// testChain is a signed root, test. and example.test. served from one
+// handler. Each zone has a single key, and the root's DS replaces the IANA
+// trust anchors for the duration of the test.
+type testChain struct {
+	t       *testing.T
+	keys    map[string]*dns.DNSKEY
+	privs   map[string]crypto.Signer
+	records []dns.RR
+}
+
+func newTestChain(t *testing.T) *testChain {
+	t.Helper()
+
+	c := &testChain{t: t, keys: map[string]*dns.DNSKEY{}, privs: map[string]crypto.Signer{}}
+	for _, zone := range []string{".", "test.", "example.test."} {
+		c.keys[zone], c.privs[zone] = newTestKey(t, zone, 257)
+	}
+
+	original := rootTrustAnchors
+	rootTrustAnchors = []*dns.DS{c.keys["."].ToDS(dns.SHA256)}
+	t.Cleanup(func() { rootTrustAnchors = original })
+
+	// Every zone is signed, and test. vouches for nothing yet
+	for _, zone := range []string{".", "test.", "example.test."} {
+		c.add(zone, zone+" 3600 IN SOA ns.example.test. hostmaster.example.test. 1 7200 3600 1209600 300")
+		c.addRRs(zone, c.keys[zone])
+	}
+	c.addRRs(".", c.keys["test."].ToDS(dns.SHA256))
+	c.add("example.test.", "example.test. 300 IN A 192.0.2.1")
+
+	return c
+}
+
+// add signs the RRset parsed from records with the key of signer.
+func (c *testChain) add(signer string, records ...string) {
+	c.t.Helper()
+
+	var rrset []dns.RR
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		if err != nil {
+			c.t.Fatalf("Invalid test record %q: %v", record, err)
+		}
+		rrset = append(rrset, rr)
+	}
+	c.addRRs(signer, rrset...)
+}
+
+// addRRs signs rrset with the key of signer, valid for the next day.
+func (c *testChain) addRRs(signer string, rrset ...dns.RR) {
+	c.t.Helper()
+	now := time.Now()
+	c.addSigned(signer, now.Add(-time.Hour), now.Add(24*time.Hour), rrset...)
+}
+
+func (c *testChain) addSigned(signer string, inception, expiration time.Time, rrset ...dns.RR) {
+	c.t.Helper()
+	sig := signRRset(c.t, c.keys[signer], c.privs[signer], rrset, inception, expiration)
+	c.records = append(c.records, rrset...)
+	c.records = append(c.records, sig)
+}
+
+// remove drops the records, and their RRSIGs, of the given name and type.
+func (c *testChain) remove(name string, rrtype uint16) {
+	kept := c.records[:0]
+	for _, rr := range c.records {
+		covered := rr.Header().Rrtype
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			covered = sig.TypeCovered
+		}
+		if !strings.EqualFold(rr.Header().Name, name) || covered != rrtype {
+			kept = append(kept, rr)
+		}
+	}
+	c.records = kept
+}
+
+// handler answers from the records. Empty answers carry every NSEC and
+// NSEC3 record, with its RRSIGs, in the authority section.
+func (c *testChain) handler(w dns.ResponseWriter, r *dns.Msg) {
+	m := &dns.Msg{}
+	m.SetReply(r)
+	m.Authoritative = true
+
+	q := r.Question[0]
+	nameExists := false
+	var denial []dns.RR
+	for _, rr := range c.records {
+		covered := rr.Header().Rrtype
+		if sig, ok := rr.(*dns.RRSIG); ok {
+			covered = sig.TypeCovered
+		}
+		if covered == dns.TypeNSEC || covered == dns.TypeNSEC3 {
+			denial = append(denial, rr)
+		}
+		if !strings.EqualFold(rr.Header().Name, q.Name) {
+			continue
+		}
+		nameExists = true
+		if covered == q.Qtype {
+			m.Answer = append(m.Answer, dns.Copy(rr))
+		}
+	}
+
+	if !nameExists {
+		m.Rcode = dns.RcodeNameError
+	}
+	if len(m.Answer) == 0 {
+		m.Ns = denial
+	}
+	w.WriteMsg(m)
+}
+
+func (c *testChain) resolver() Resolver {
+	c.t.Helper()
+	return NewUpstreamResolver([]string{startTestDNSServer(c.t, dns.HandlerFunc(c.handler))}, time.Second)
+}
+
+func TestWalkChainOfTrust(t *testing.T) {
+	tests := []struct {
+		name     string
+		setup    func(c *testChain)
+		statuses []string
+		wantErr  string
+	}{
+		{
+			name: "secure delegation",
+			setup: func(c *testChain) {
+				c.addRRs("test.", c.keys["example.test."].ToDS(dns.SHA256))
+			},
+			statuses: []string{models.DNSSECStatusSecure, models.DNSSECStatusSecure, models.DNSSECStatusSecure},
+		},
+		{
+			name: "missing DS proven by NSEC",
+			setup: func(c *testChain) {
+				c.add("test.", "example.test. 3600 IN NSEC zzz.test. NS RRSIG NSEC")
+			},
+			statuses: []string{models.DNSSECStatusSecure, models.DNSSECStatusSecure, models.DNSSECStatusInsecure},
+			wantErr:  "missing DS at parent: zone publishes DNSKEY",
+		},
+		{
+			name: "missing DS proven by NSEC3",
+			setup: func(c *testChain) {
+				hash := dns.HashName("example.test.", dns.SHA1, 0, "")
+				c.add("test.", strings.ToLower(hash)+".test. 3600 IN NSEC3 1 0 0 - "+hash+" NS")
+			},
+			statuses: []string{models.DNSSECStatusSecure, models.DNSSECStatusSecure, models.DNSSECStatusInsecure},
+			wantErr:  "missing DS at parent: zone publishes DNSKEY",
+		},
+		{
+			name: "missing DS in an NSEC3 opt-out span",
+			setup: func(c *testChain) {
+				// The apex NSEC3 points at itself, so it covers every other name
+				hash := dns.HashName("test.", dns.SHA1, 0, "")
+				c.add("test.", strings.ToLower(hash)+".test. 3600 IN NSEC3 1 1 0 - "+hash+" NS SOA RRSIG DNSKEY NSEC3PARAM")
+			},
+			statuses: []string{models.DNSSECStatusSecure, models.DNSSECStatusSecure, models.DNSSECStatusInsecure},
+			wantErr:  "missing DS at parent: zone publishes DNSKEY",
+		},
+		{
+			name:     "missing DS without proof",
+			setup:    func(c *testChain) {},
+			statuses: []string{models.DNSSECStatusSecure, models.DNSSECStatusSecure, models.DNSSECStatusBogus},
+			wantErr:  "missing DS at parent is not proven: no NSEC or NSEC3 records",
+		},
+		{
+			name: "missing DS with unsigned NSEC",
+			setup: func(c *testChain) {
+				nsec, _ := dns.NewRR("example.test. 3600 IN NSEC zzz.test. NS RRSIG NSEC")
+				c.records = append(c.records, nsec)
+			},
+			statuses: []string{models.DNSSECStatusSecure, models.DNSSECStatusSecure, models.DNSSECStatusBogus},
+			wantErr:  "no RRSIG records found",
+		},
+		{
+			name: "NSEC lists the DS it denies",
+			setup: func(c *testChain) {
+				c.add("test.", "example.test. 3600 IN NSEC zzz.test. NS DS RRSIG NSEC")
+			},
+			statuses: []string{models.DNSSECStatusSecure, models.DNSSECStatusSecure, models.DNSSECStatusBogus},
+			wantErr:  "denial record lists a DS RRset",
+		},
+		{
+			name: "DS does not match DNSKEY",
+			setup: func(c *testChain) {
+				other, _ := newTestKey(t, "example.test.", 257)
+				c.addRRs("test.", other.ToDS(dns.SHA256))
+			},
+			statuses: []string{models.DNSSECStatusSecure, models.DNSSECStatusSecure, models.DNSSECStatusBogus},
+			wantErr:  "key mismatch",
+		},
+		{
+			name: "expired DNSKEY signature",
+			setup: func(c *testChain) {
+				c.addRRs("test.", c.keys["example.test."].ToDS(dns.SHA256))
+				c.remove("example.test.", dns.TypeDNSKEY)
+				now := time.Now()
+				c.addSigned("example.test.", now.Add(-48*time.Hour), now.Add(-24*time.Hour), c.keys["example.test."])
+			},
+			statuses: []string{models.DNSSECStatusSecure, models.DNSSECStatusSecure, models.DNSSECStatusBogus},
+			wantErr:  "validity period",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestChain(t)
+			tt.setup(c)
+
+			chain, err := walkChainOfTrust(context.Background(), c.resolver(), "example.test")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			var statuses []string
+			for _, link := range chain {
+				statuses = append(statuses, link.Status)
+			}
+			if !reflect.DeepEqual(statuses, tt.statuses) {
+				t.Fatalf("Expected statuses %v, got %+v", tt.statuses, chain)
+			}
+
+			last := chain[len(chain)-1]
+			if !strings.Contains(last.Error, tt.wantErr) {
+				t.Errorf("Expected error containing '%s', got: %q", tt.wantErr, last.Error)
+			}
+		})
+	}
+}
+
+func TestCheckDNSSEC(t *testing.T) {
+	secure := newTestChain(t)
+	secure.addRRs("test.", secure.keys["example.test."].ToDS(dns.SHA256))
+
+	result := CheckDNSSEC(context.Background(), secure.resolver(), "example.test")
+	if !result.Enabled || !result.Valid || result.Error != "" {
+		t.Errorf("Expected a valid chain, got %+v", result)
+	}
+	if len(result.Chain) != 3 {
+		t.Errorf("Expected 3 zones in the chain, got %d", len(result.Chain))
+	}
+
+	bogus := newTestChain(t)
+	other, _ := newTestKey(t, "example.test.", 257)
+	bogus.addRRs("test.", other.ToDS(dns.SHA256))
+
+	result = CheckDNSSEC(context.Background(), bogus.resolver(), "example.test")
+	if !result.Enabled || result.Valid {
+		t.Errorf("Expected an enabled but invalid chain, got %+v", result)
+	}
+	if !strings.HasPrefix(result.Error, "example.test.: key mismatch") {
+		t.Errorf("Expected key mismatch at example.test., got %q", result.Error)
+	}
+}
@@ -0,0 +1,212 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// Algorithms that must not be used for signing (RFC 8624 section 3.1)
+var deprecatedAlgorithms = map[uint8]bool{
+	dns.RSAMD5:           true,
+	dns.DSA:              true,
+	dns.RSASHA1:          true,
+	dns.DSANSEC3SHA1:     true,
+	dns.RSASHA1NSEC3SHA1: true,
+	dns.ECCGOST:          true,
+}
+
+// DS digest types that must not be used (RFC 8624 section 3.3)
+var deprecatedDigests = map[uint8]bool{
+	dns.SHA1:   true,
+	dns.GOST94: true,
+}
+
+// minRSAKeyBits is the smallest RSA modulus not flagged as weak.
+const minRSAKeyBits = 2048
+
+// inspectZoneSigning reports the keys, DS records and signatures of a signed
+// zone, and flags deprecated algorithms and signatures close to expiring.
+func inspectZoneSigning(ctx context.Context, resolver Resolver, zone string, now time.Time) (*models.DNSSECSigning, error) {
+	signing := &models.DNSSECSigning{Zone: normalizeHost(zone)}
+
+	keyRRs, keySigs, err := queryRRset(ctx, resolver, zone, dns.TypeDNSKEY)
+	if err != nil {
+		return nil, err
+	}
+	keys := toDNSKEY(keyRRs)
+	for _, key := range keys {
+		role := models.DNSSECRoleZSK
+		if key.Flags&dns.SEP != 0 {
+			role = models.DNSSECRoleKSK
+		}
+		bits := dnskeyBits(key)
+		signing.Keys = append(signing.Keys, models.DNSSECKey{
+			KeyTag:    key.KeyTag(),
+			Role:      role,
+			Algorithm: algorithmName(key.Algorithm),
+			Bits:      bits,
+		})
+
+		if deprecatedAlgorithms[key.Algorithm] {
+			signing.Issues = append(signing.Issues,
+				fmt.Sprintf("%s %d uses deprecated algorithm %s", role, key.KeyTag(), algorithmName(key.Algorithm)))
+		} else if isRSA(key.Algorithm) && bits > 0 && bits < minRSAKeyBits {
+			signing.Issues = append(signing.Issues,
+				fmt.Sprintf("%s %d is a %d-bit RSA key, below %d bits", role, key.KeyTag(), bits, minRSAKeyBits))
+		}
+	}
+
+	dsRRs, _, err := queryRRset(ctx, resolver, zone, dns.TypeDS)
+	if err != nil {
+		return nil, err
+	}
+	parentDS := toDS(dsRRs)
+	for _, ds := range parentDS {
+		digest := dns.HashToString[ds.DigestType]
+		if digest == "" {
+			digest = fmt.Sprintf("%d", ds.DigestType)
+		}
+		signing.DS = append(signing.DS, models.DNSSECDS{
+			KeyTag:     ds.KeyTag,
+			Algorithm:  algorithmName(ds.Algorithm),
+			DigestType: digest,
+		})
+
+		if deprecatedDigests[ds.DigestType] {
+			signing.Issues = append(signing.Issues,
+				fmt.Sprintf("DS %d uses deprecated digest type %s", ds.KeyTag, digest))
+		}
+	}
+
+	// CDS/CDNSKEY records signal the next DS set to the parent. Some servers
+	// mishandle these newer types, which shouldn't hide the rest of the report
+	cdsRRs, _, cdsErr := queryRRset(ctx, resolver, zone, dns.TypeCDS)
+	cdnskeyRRs, _, cdnskeyErr := queryRRset(ctx, resolver, zone, dns.TypeCDNSKEY)
+	signing.Rollover = checkRollover(parentDS, keys, toCDS(cdsRRs), toCDNSKEY(cdnskeyRRs))
+	for _, err := range []error{cdsErr, cdnskeyErr} {
+		if err != nil {
+			signing.Rollover.Issues = append(signing.Rollover.Issues, fmt.Sprintf("Could not check for CDS/CDNSKEY: %v", err))
+		}
+	}
+
+	_, soaSigs, err := queryRRset(ctx, resolver, zone, dns.TypeSOA)
+	if err != nil {
+		return nil, err
+	}
+	for _, sig := range append(keySigs, soaSigs...) {
+		expiresAt := rrsigTime(sig.Expiration, now)
+		signature := models.DNSSECSignature{
+			TypeCovered:   dns.TypeToString[sig.TypeCovered],
+			KeyTag:        sig.KeyTag,
+			Algorithm:     algorithmName(sig.Algorithm),
+			Inception:     rrsigTime(sig.Inception, now),
+			ExpiresAt:     expiresAt,
+			ExpiresInDays: models.CalculateDaysUntilExpiration(expiresAt),
+			Status:        models.CalculateSignatureStatus(expiresAt),
+		}
+		signing.Signatures = append(signing.Signatures, signature)
+
+		switch signature.Status {
+		case models.StatusExpired:
+			signing.Issues = append(signing.Issues,
+				fmt.Sprintf("RRSIG over %s by key %d expired on %s", signature.TypeCovered, sig.KeyTag,
+					expiresAt.UTC().Format("2006-01-02 15:04")))
+		case models.StatusExpiringSoon:
+			signing.Issues = append(signing.Issues,
+				fmt.Sprintf("RRSIG over %s by key %d expires on %s", signature.TypeCovered, sig.KeyTag,
+					expiresAt.UTC().Format("2006-01-02 15:04")))
+		}
+	}
+
+	return signing, nil
+}
+
+func toCDS(rrs []dns.RR) []*dns.DS {
+	cds := make([]*dns.DS, 0, len(rrs))
+	for _, rr := range rrs {
+		if c, ok := rr.(*dns.CDS); ok {
+			cds = append(cds, &c.DS)
+		}
+	}
+	return cds
+}
+
+func toCDNSKEY(rrs []dns.RR) []*dns.DNSKEY {
+	keys := make([]*dns.DNSKEY, 0, len(rrs))
+	for _, rr := range rrs {
+		if c, ok := rr.(*dns.CDNSKEY); ok {
+			keys = append(keys, &c.DNSKEY)
+		}
+	}
+	return keys
+}
+
+// dnskeyBits returns the key size of a DNSKEY, or 0 when unknown.
+func dnskeyBits(key *dns.DNSKEY) int {
+	switch key.Algorithm {
+	case dns.ECDSAP256SHA256, dns.ED25519:
+		return 256
+	case dns.ECDSAP384SHA384:
+		return 384
+	case dns.ED448:
+		return 456
+	case dns.ECCGOST:
+		return 512
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(key.PublicKey)
+	if err != nil || len(raw) == 0 {
+		return 0
+	}
+
+	switch {
+	case isRSA(key.Algorithm):
+		// RFC 3110: exponent length, exponent, modulus
+		explen, offset := int(raw[0]), 1
+		if explen == 0 {
+			if len(raw) < 3 {
+				return 0
+			}
+			explen, offset = int(raw[1])<<8|int(raw[2]), 3
+		}
+		modulus := len(raw) - offset - explen
+		if modulus <= 0 {
+			return 0
+		}
+		return modulus * 8
+	case key.Algorithm == dns.DSA || key.Algorithm == dns.DSANSEC3SHA1:
+		// RFC 2536: T, Q, P, G, Y with P of 64 + T*8 octets
+		return (64 + int(raw[0])*8) * 8
+	}
+
+	return 0
+}
+
+func isRSA(algorithm uint8) bool {
+	switch algorithm {
+	case dns.RSAMD5, dns.RSASHA1, dns.RSASHA1NSEC3SHA1, dns.RSASHA256, dns.RSASHA512:
+		return true
+	}
+	return false
+}
+
+func algorithmName(algorithm uint8) string {
+	if name, ok := dns.AlgorithmToString[algorithm]; ok {
+		return name
+	}
+	return fmt.Sprintf("%d", algorithm)
+}
+
+// rrsigTime converts an RRSIG timestamp, a 32-bit value using serial number
+// arithmetic (RFC 4034 section 3.1.5), to the time closest to now.
+func rrsigTime(value uint32, now time.Time) time.Time {
+	utc := now.UTC().Unix()
+	delta := int32(value - uint32(utc))
+	return time.Unix(utc+int64(delta), 0).UTC()
+}
@@ -0,0 +1,202 @@
+package tools
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// signedZone answers like testZone, adding the RRSIGs that cover the
+// queried type.
+func signedZone(t *testing.T, records ...dns.RR) dns.HandlerFunc {
+	t.Helper()
+
+	return func(w dns.ResponseWriter, r *dns.Msg) {
+		m := &dns.Msg{}
+		m.SetReply(r)
+		m.Authoritative = true
+
+		q := r.Question[0]
+		for _, rr := range records {
+			if !strings.EqualFold(rr.Header().Name, q.Name) {
+				continue
+			}
+			if sig, ok := rr.(*dns.RRSIG); ok && sig.TypeCovered == q.Qtype {
+				m.Answer = append(m.Answer, dns.Copy(rr))
+			} else if rr.Header().Rrtype == q.Qtype {
+				m.Answer = append(m.Answer, dns.Copy(rr))
+			}
+		}
+
+		w.WriteMsg(m)
+	}
+}
+
+func generateTestKey(t *testing.T, flags uint16, algorithm uint8, bits int) *dns.DNSKEY {
+	t.Helper()
+
+	key := &dns.DNSKEY{
+		Hdr:       dns.RR_Header{Name: "example.test.", Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 3600},
+		Flags:     flags,
+		Protocol:  3,
+		Algorithm: algorithm,
+	}
+	if _, err := key.Generate(bits); err != nil {
+		t.Fatalf("Failed to generate key: %v", err)
+	}
+	return key
+}
+
+func testRRSIG(key *dns.DNSKEY, covered uint16, inception, expiration time.Time) *dns.RRSIG {
+	return &dns.RRSIG{
+		Hdr:         dns.RR_Header{Name: "example.test.", Rrtype: dns.TypeRRSIG, Class: dns.ClassINET, Ttl: 3600},
+		TypeCovered: covered,
+		Algorithm:   key.Algorithm,
+		Labels:      2,
+		OrigTtl:     3600,
+		Expiration:  uint32(expiration.Unix()),
+		Inception:   uint32(inception.Unix()),
+		KeyTag:      key.KeyTag(),
+		SignerName:  "example.test.",
+		Signature:   "AAAA",
+	}
+}
+
+func TestInspectZoneSigning(t *testing.T) {
+	now := time.Now()
+	ksk := generateTestKey(t, 257, dns.ECDSAP256SHA256, 256)
+	zsk := generateTestKey(t, 256, dns.RSASHA1, 1024)
+
+	soa, _ := dns.NewRR("example.test. 3600 IN SOA ns1.example.test. hostmaster.example.test. 1 7200 3600 1209600 300")
+	ds := ksk.ToDS(dns.SHA1)
+
+	addr := startTestDNSServer(t, signedZone(t,
+		ksk, zsk, soa, ds,
+		testRRSIG(ksk, dns.TypeDNSKEY, now.Add(-24*time.Hour), now.Add(14*24*time.Hour)),
+		testRRSIG(zsk, dns.TypeSOA, now.Add(-13*24*time.Hour), now.Add(6*time.Hour)),
+	))
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+	signing, err := inspectZoneSigning(context.Background(), resolver, "example.test.", now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if signing.Zone != "example.test" {
+		t.Errorf("Expected zone example.test, got %s", signing.Zone)
+	}
+
+	if len(signing.Keys) != 2 {
+		t.Fatalf("Expected 2 keys, got %+v", signing.Keys)
+	}
+	expectedKeys := []models.DNSSECKey{
+		{KeyTag: ksk.KeyTag(), Role: models.DNSSECRoleKSK, Algorithm: "ECDSAP256SHA256", Bits: 256},
+		{KeyTag: zsk.KeyTag(), Role: models.DNSSECRoleZSK, Algorithm: "RSASHA1", Bits: 1024},
+	}
+	for i, want := range expectedKeys {
+		if signing.Keys[i] != want {
+			t.Errorf("Expected key %+v, got %+v", want, signing.Keys[i])
+		}
+	}
+
+	if len(signing.DS) != 1 || signing.DS[0].DigestType != "SHA1" || signing.DS[0].KeyTag != ksk.KeyTag() {
+		t.Errorf("Expected SHA1 DS for the KSK, got %+v", signing.DS)
+	}
+
+	if len(signing.Signatures) != 2 {
+		t.Fatalf("Expected 2 signatures, got %+v", signing.Signatures)
+	}
+	if sig := signing.Signatures[0]; sig.TypeCovered != "DNSKEY" || sig.Status != models.StatusActive || sig.ExpiresInDays != 13 {
+		t.Errorf("Expected active DNSKEY signature, got %+v", sig)
+	}
+	if sig := signing.Signatures[1]; sig.TypeCovered != "SOA" || sig.Status != models.StatusExpiringSoon {
+		t.Errorf("Expected SOA signature expiring soon, got %+v", sig)
+	}
+
+	expectedIssues := []string{
+		"ZSK " + itoa(zsk.KeyTag()) + " uses deprecated algorithm RSASHA1",
+		"DS " + itoa(ksk.KeyTag()) + " uses deprecated digest type SHA1",
+		"RRSIG over SOA by key " + itoa(zsk.KeyTag()) + " expires on",
+	}
+	if len(signing.Issues) != len(expectedIssues) {
+		t.Fatalf("Expected %d issues, got %v", len(expectedIssues), signing.Issues)
+	}
+	for i, want := range expectedIssues {
+		if !strings.HasPrefix(signing.Issues[i], want) {
+			t.Errorf("Expected issue %q, got %q", want, signing.Issues[i])
+		}
+	}
+}
+
+func TestInspectZoneSigning_CDSFailure(t *testing.T) {
+	now := time.Now()
+	ksk := generateTestKey(t, 257, dns.ECDSAP256SHA256, 256)
+	zone := signedZone(t,
+		ksk, ksk.ToDS(dns.SHA256),
+		testRRSIG(ksk, dns.TypeDNSKEY, now.Add(-24*time.Hour), now.Add(14*24*time.Hour)),
+	)
+
+	// The server fails queries for the CDS and CDNSKEY types
+	addr := startTestDNSServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		if qtype := r.Question[0].Qtype; qtype == dns.TypeCDS || qtype == dns.TypeCDNSKEY {
+			m := &dns.Msg{}
+			m.SetRcode(r, dns.RcodeServerFailure)
+			w.WriteMsg(m)
+			return
+		}
+		zone(w, r)
+	}))
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+	signing, err := inspectZoneSigning(context.Background(), resolver, "example.test.", now)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if len(signing.Keys) != 1 || len(signing.DS) != 1 || len(signing.Signatures) != 1 {
+		t.Errorf("Expected the key, DS and signature to be reported, got %+v", signing)
+	}
+
+	if len(signing.Rollover.Issues) != 2 || !strings.Contains(signing.Rollover.Issues[0], "CDS query for example.test. returned SERVFAIL") {
+		t.Errorf("Expected CDS and CDNSKEY failures in rollover issues, got %v", signing.Rollover.Issues)
+	}
+}
+
+func TestDNSKEYBits(t *testing.T) {
+	tests := []struct {
+		algorithm uint8
+		bits      int
+	}{
+		{algorithm: dns.RSASHA256, bits: 2048},
+		{algorithm: dns.RSASHA512, bits: 1024},
+		{algorithm: dns.ECDSAP384SHA384, bits: 384},
+		{algorithm: dns.ED25519, bits: 256},
+	}
+
+	for _, tt := range tests {
+		key := generateTestKey(t, 256, tt.algorithm, tt.bits)
+		if got := dnskeyBits(key); got != tt.bits {
+			t.Errorf("%s: expected %d bits, got %d", dns.AlgorithmToString[tt.algorithm], tt.bits, got)
+		}
+	}
+}
+
+func TestRRSIGTime(t *testing.T) {
+	now := time.Date(2106, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// 2106-03-01 wraps past 2^32 seconds
+	expiration := time.Date(2106, 3, 1, 0, 0, 0, 0, time.UTC)
+	if got := rrsigTime(uint32(expiration.Unix()), now); !got.Equal(expiration) {
+		t.Errorf("Expected %s, got %s", expiration, got)
+	}
+}
+
+func itoa(v uint16) string {
+	return strconv.Itoa(int(v))
+}
@@ -0,0 +1,109 @@
+package tools
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// checkRollover compares the DS records held by the parent with the zone's
+// DNSKEY set, and with the CDS/CDNSKEY records the zone publishes to ask the
+// parent for new DS records (RFC 7344, RFC 8078).
+func checkRollover(ds []*dns.DS, keys []*dns.DNSKEY, cds []*dns.DS, cdnskeys []*dns.DNSKEY) models.DNSSECRollover {
+	rollover := models.DNSSECRollover{}
+
+	// DS records that no key hashes to
+	for _, d := range ds {
+		if len(matchDS([]*dns.DS{d}, keys)) == 0 {
+			rollover.OrphanedDS = append(rollover.OrphanedDS, d.KeyTag)
+		}
+	}
+	if len(ds) > 0 && len(rollover.OrphanedDS) == len(ds) {
+		rollover.Issues = append(rollover.Issues,
+			fmt.Sprintf("No DS at the parent matches a DNSKEY (%s): the zone fails validation", formatKeyTags(rollover.OrphanedDS)))
+	} else if len(rollover.OrphanedDS) > 0 {
+		rollover.Issues = append(rollover.Issues,
+			fmt.Sprintf("Orphaned DS at the parent matches no DNSKEY: %s", formatKeyTags(rollover.OrphanedDS)))
+	}
+
+	// A single CDS "0 0 0 00" or CDNSKEY "0 3 0 AA==" asks for removal of all DS
+	for _, c := range cds {
+		if c.Algorithm == 0 {
+			rollover.DeleteRequested = true
+		}
+	}
+	for _, c := range cdnskeys {
+		if c.Algorithm == 0 {
+			rollover.DeleteRequested = true
+		}
+	}
+	if rollover.DeleteRequested {
+		rollover.Status = models.RolloverDelete
+		rollover.Issues = append(rollover.Issues, "CDS/CDNSKEY delete request: the zone asks the parent to remove its DS records")
+		return rollover
+	}
+
+	if len(cds) == 0 && len(cdnskeys) == 0 {
+		return rollover
+	}
+
+	// Keys the child asks the parent to publish DS records for
+	requested := map[uint16]bool{}
+	for _, c := range cds {
+		rollover.CDS = append(rollover.CDS, c.KeyTag)
+		requested[c.KeyTag] = true
+		if len(matchDS([]*dns.DS{c}, keys)) == 0 {
+			rollover.Issues = append(rollover.Issues,
+				fmt.Sprintf("CDS %d matches no DNSKEY: the parent cannot accept it", c.KeyTag))
+			rollover.Status = models.RolloverStuck
+		}
+	}
+	for _, c := range cdnskeys {
+		rollover.CDNSKEY = append(rollover.CDNSKEY, c.KeyTag())
+		requested[c.KeyTag()] = true
+		if !slices.ContainsFunc(keys, func(k *dns.DNSKEY) bool { return k.PublicKey == c.PublicKey }) {
+			rollover.Issues = append(rollover.Issues,
+				fmt.Sprintf("CDNSKEY %d matches no DNSKEY: the parent cannot accept it", c.KeyTag()))
+			rollover.Status = models.RolloverStuck
+		}
+	}
+	if len(cds) > 0 && len(cdnskeys) > 0 && !slices.Equal(slices.Sorted(slices.Values(rollover.CDS)), slices.Sorted(slices.Values(rollover.CDNSKEY))) {
+		rollover.Issues = append(rollover.Issues, "CDS and CDNSKEY records refer to different keys")
+	}
+
+	if rollover.Status == models.RolloverStuck {
+		return rollover
+	}
+
+	current := map[uint16]bool{}
+	for _, d := range ds {
+		current[d.KeyTag] = true
+	}
+	if !maps.Equal(current, requested) {
+		rollover.Status = models.RolloverPending
+		rollover.Issues = append(rollover.Issues,
+			fmt.Sprintf("Key rollover pending: parent DS %s, zone requests %s",
+				formatKeyTags(slices.Sorted(maps.Keys(current))), formatKeyTags(slices.Sorted(maps.Keys(requested)))))
+		return rollover
+	}
+
+	rollover.Status = models.RolloverInSync
+	return rollover
+}
+
+func formatKeyTags(tags []uint16) string {
+	if len(tags) == 0 {
+		return "none"
+	}
+	parts := make([]string, len(tags))
+	for i, tag := range tags {
+		parts[i] = strconv.Itoa(int(tag))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+func TestCheckRollover(t *testing.T) {
+	oldKSK := generateTestKey(t, 257, dns.ECDSAP256SHA256, 256)
+	newKSK := generateTestKey(t, 257, dns.ECDSAP256SHA256, 256)
+	retired := generateTestKey(t, 257, dns.ECDSAP256SHA256, 256)
+
+	deleteCDS := &dns.DS{Algorithm: 0, DigestType: 0, Digest: "00"}
+	deleteCDNSKEY := &dns.DNSKEY{Flags: 0, Protocol: 3, Algorithm: 0, PublicKey: "AA=="}
+
+	tests := []struct {
+		name     string
+		ds       []*dns.DS
+		keys     []*dns.DNSKEY
+		cds      []*dns.DS
+		cdnskeys []*dns.DNSKEY
+		status   string
+		orphaned int
+		issue    string
+	}{
+		{
+			name: "no CDS published",
+			ds:   []*dns.DS{oldKSK.ToDS(dns.SHA256)},
+			keys: []*dns.DNSKEY{oldKSK},
+		},
+		{
+			name:     "in sync",
+			ds:       []*dns.DS{oldKSK.ToDS(dns.SHA256)},
+			keys:     []*dns.DNSKEY{oldKSK},
+			cds:      []*dns.DS{oldKSK.ToDS(dns.SHA256)},
+			cdnskeys: []*dns.DNSKEY{oldKSK},
+			status:   models.RolloverInSync,
+		},
+		{
+			name:   "rollover pending",
+			ds:     []*dns.DS{oldKSK.ToDS(dns.SHA256)},
+			keys:   []*dns.DNSKEY{oldKSK, newKSK},
+			cds:    []*dns.DS{newKSK.ToDS(dns.SHA256)},
+			status: models.RolloverPending,
+			issue:  "Key rollover pending",
+		},
+		{
+			name:   "CDS for a key not in the zone",
+			ds:     []*dns.DS{oldKSK.ToDS(dns.SHA256)},
+			keys:   []*dns.DNSKEY{oldKSK},
+			cds:    []*dns.DS{newKSK.ToDS(dns.SHA256)},
+			status: models.RolloverStuck,
+			issue:  "matches no DNSKEY: the parent cannot accept it",
+		},
+		{
+			name:     "orphaned DS",
+			ds:       []*dns.DS{oldKSK.ToDS(dns.SHA256), retired.ToDS(dns.SHA256)},
+			keys:     []*dns.DNSKEY{oldKSK},
+			orphaned: 1,
+			issue:    "Orphaned DS at the parent",
+		},
+		{
+			name:     "no DS matches",
+			ds:       []*dns.DS{retired.ToDS(dns.SHA256)},
+			keys:     []*dns.DNSKEY{oldKSK},
+			orphaned: 1,
+			issue:    "the zone fails validation",
+		},
+		{
+			name:     "delete request",
+			ds:       []*dns.DS{oldKSK.ToDS(dns.SHA256)},
+			keys:     []*dns.DNSKEY{oldKSK},
+			cds:      []*dns.DS{deleteCDS},
+			cdnskeys: []*dns.DNSKEY{deleteCDNSKEY},
+			status:   models.RolloverDelete,
+			issue:    "delete request",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rollover := checkRollover(tt.ds, tt.keys, tt.cds, tt.cdnskeys)
+
+			if rollover.Status != tt.status {
+				t.Errorf("Expected status %q, got %q", tt.status, rollover.Status)
+			}
+
+			if len(rollover.OrphanedDS) != tt.orphaned {
+				t.Errorf("Expected %d orphaned DS, got %v", tt.orphaned, rollover.OrphanedDS)
+			}
+
+			if tt.issue == "" {
+				if len(rollover.Issues) != 0 {
+					t.Errorf("Expected no issues, got %v", rollover.Issues)
+				}
+				return
+			}
+			found := false
+			for _, issue := range rollover.Issues {
+				if strings.Contains(issue, tt.issue) {
+					found = true
+				}
+			}
+			if !found {
+				t.Errorf("Expected issue %q, got %v", tt.issue, rollover.Issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,295 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// ednsUnknownOption is an unassigned EDNS option code, which servers must ignore.
+const ednsUnknownOption = 100
+
+// ednsSmallBuffer is the UDP buffer advertised to force truncation of large answers.
+const ednsSmallBuffer = 512
+
+// ednsProbe is one compliance test, modelled on the ISC EDNS compliance
+// tester. It returns whether the server passed and, if not, why.
+type ednsProbe struct {
+	name string
+	run  func(ctx context.Context, server, zone string) (bool, string)
+}
+
+var ednsProbes = []ednsProbe{
+	{name: "edns", run: probeEDNS},
+	{name: "edns1", run: probeEDNSVersion},
+	{name: "ednsopt", run: probeEDNSOption},
+	{name: "do", run: probeDOBit},
+	{name: "bufsize", run: probeTruncation},
+	{name: "tcp", run: probeTCP},
+}
+
+// CheckEDNSCompliance runs the EDNS and TCP compliance probes against every
+// authoritative nameserver in the analysis, on the first address that passes
+// the most tests.
+func CheckEDNSCompliance(ctx context.Context, domain string, analysis *models.NameserverAnalysis) {
+	zone := dns.Fqdn(normalizeDomain(domain))
+
+	var wg sync.WaitGroup
+	for i := range analysis.Servers {
+		server := &analysis.Servers[i]
+		if !server.Authoritative {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			best := -1
+			for _, ip := range server.Addresses {
+				results := CheckEDNS(ctx, net.JoinHostPort(ip, nameserverPort), zone)
+				if passed := countPassed(results); passed > best {
+					best = passed
+					server.EDNS = results
+				}
+				if best == len(ednsProbes) {
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, server := range analysis.Servers {
+		if countFailed(server.EDNS) > 0 {
+			analysis.EDNSFailures = append(analysis.EDNSFailures, server.Host)
+		}
+	}
+}
+
+// CheckEDNS runs every compliance probe against server (host:port). A probe
+// that fails because ctx ended is marked inconclusive rather than failed, as
+// it says nothing about the server.
+func CheckEDNS(ctx context.Context, server, zone string) []models.EDNSProbe {
+	results := make([]models.EDNSProbe, len(ednsProbes))
+
+	var wg sync.WaitGroup
+	for i, probe := range ednsProbes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			passed, detail := probe.run(ctx, server, zone)
+			results[i] = models.EDNSProbe{Test: probe.name, Passed: passed, Detail: detail}
+			if err := contextEnded(ctx); !passed && err != nil {
+				results[i].Inconclusive = true
+				results[i].Detail = err.Error()
+			}
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+// contextEnded returns the reason ctx has ended, or nil. A passed deadline
+// counts even before ctx reports it, since the socket deadline derived from
+// it can expire first.
+func contextEnded(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok && !time.Now().Before(deadline) {
+		return context.DeadlineExceeded
+	}
+	return nil
+}
+
+func countPassed(results []models.EDNSProbe) int {
+	passed := 0
+	for _, result := range results {
+		if result.Passed {
+			passed++
+		}
+	}
+	return passed
+}
+
+func countFailed(results []models.EDNSProbe) int {
+	failed := 0
+	for _, result := range results {
+		if !result.Passed && !result.Inconclusive {
+			failed++
+		}
+	}
+	return failed
+}
+
+// probeEDNS sends a plain EDNS0 query, which must be answered with an
+// EDNS0 OPT record.
+func probeEDNS(ctx context.Context, server, zone string) (bool, string) {
+	msg := ednsQuery(zone, dns.TypeSOA)
+
+	resp, err := ednsExchange(ctx, "udp", server, msg)
+	if err != nil {
+		return false, err.Error()
+	}
+	opt := resp.IsEdns0()
+	switch {
+	case resp.Rcode != dns.RcodeSuccess:
+		return false, fmt.Sprintf("expected NOERROR, got %s", dns.RcodeToString[resp.Rcode])
+	case opt == nil:
+		return false, "no OPT record in response"
+	case opt.Version() != 0:
+		return false, fmt.Sprintf("expected EDNS version 0, got %d", opt.Version())
+	case !hasType(resp.Answer, dns.TypeSOA):
+		return false, "no SOA in answer"
+	}
+	return true, ""
+}
+
+// probeEDNSVersion sends EDNS version 1, which must be refused with BADVERS
+// and an OPT record of the highest version supported (RFC 6891 section 6.1.3).
+func probeEDNSVersion(ctx context.Context, server, zone string) (bool, string) {
+	msg := ednsQuery(zone, dns.TypeSOA)
+	msg.IsEdns0().SetVersion(1)
+
+	resp, err := ednsExchange(ctx, "udp", server, msg)
+	if err != nil {
+		return false, err.Error()
+	}
+	opt := resp.IsEdns0()
+	switch {
+	case resp.Rcode != dns.RcodeBadVers:
+		return false, fmt.Sprintf("expected BADVERS, got %s", dns.RcodeToString[resp.Rcode])
+	case opt == nil:
+		return false, "no OPT record in response"
+	case opt.Version() != 0:
+		return false, fmt.Sprintf("expected EDNS version 0, got %d", opt.Version())
+	case len(resp.Answer) > 0:
+		return false, "answer returned for unsupported EDNS version"
+	}
+	return true, ""
+}
+
+// probeEDNSOption sends an unknown EDNS option, which must be ignored and
+// not echoed back (RFC 6891 section 6.1.2).
+func probeEDNSOption(ctx context.Context, server, zone string) (bool, string) {
+	msg := ednsQuery(zone, dns.TypeSOA)
+	opt := msg.IsEdns0()
+	opt.Option = append(opt.Option, &dns.EDNS0_LOCAL{Code: ednsUnknownOption})
+
+	resp, err := ednsExchange(ctx, "udp", server, msg)
+	if err != nil {
+		return false, err.Error()
+	}
+	respOpt := resp.IsEdns0()
+	switch {
+	case resp.Rcode != dns.RcodeSuccess:
+		return false, fmt.Sprintf("expected NOERROR, got %s", dns.RcodeToString[resp.Rcode])
+	case respOpt == nil:
+		return false, "no OPT record in response"
+	case !hasType(resp.Answer, dns.TypeSOA):
+		return false, "no SOA in answer"
+	}
+	for _, option := range respOpt.Option {
+		if option.Option() == ednsUnknownOption {
+			return false, "unknown option echoed back"
+		}
+	}
+	return true, ""
+}
+
+// probeDOBit sets the DO bit, which must be copied into the response
+// (RFC 3225 section 3).
+func probeDOBit(ctx context.Context, server, zone string) (bool, string) {
+	msg := ednsQuery(zone, dns.TypeSOA)
+	msg.IsEdns0().SetDo()
+
+	resp, err := ednsExchange(ctx, "udp", server, msg)
+	if err != nil {
+		return false, err.Error()
+	}
+	opt := resp.IsEdns0()
+	switch {
+	case resp.Rcode != dns.RcodeSuccess:
+		return false, fmt.Sprintf("expected NOERROR, got %s", dns.RcodeToString[resp.Rcode])
+	case opt == nil:
+		return false, "no OPT record in response"
+	case !opt.Do():
+		return false, "DO bit not copied to response"
+	}
+	return true, ""
+}
+
+// probeTruncation asks for the DNSKEY RRset with a 512 byte buffer. An
+// answer that doesn't fit must be truncated, and the full answer must then
+// be available over TCP.
+func probeTruncation(ctx context.Context, server, zone string) (bool, string) {
+	msg := ednsQuery(zone, dns.TypeDNSKEY)
+	opt := msg.IsEdns0()
+	opt.SetUDPSize(ednsSmallBuffer)
+	opt.SetDo()
+
+	resp, err := ednsExchange(ctx, "udp", server, msg)
+	if err != nil {
+		return false, fmt.Sprintf("UDP: %v", err)
+	}
+	if !resp.Truncated {
+		if resp.Len() > ednsSmallBuffer {
+			return false, fmt.Sprintf("%d byte response exceeds the %d byte buffer", resp.Len(), ednsSmallBuffer)
+		}
+		return true, ""
+	}
+
+	resp, err = ednsExchange(ctx, "tcp", server, msg)
+	if err != nil {
+		return false, fmt.Sprintf("truncated, but TCP fallback failed: %v", err)
+	}
+	if resp.Rcode != dns.RcodeSuccess || resp.Truncated {
+		return false, fmt.Sprintf("truncated, but TCP fallback returned %s", dns.RcodeToString[resp.Rcode])
+	}
+	return true, ""
+}
+
+// probeTCP queries the SOA over TCP, which every nameserver must support
+// (RFC 7766).
+func probeTCP(ctx context.Context, server, zone string) (bool, string) {
+	resp, err := ednsExchange(ctx, "tcp", server, ednsQuery(zone, dns.TypeSOA))
+	if err != nil {
+		return false, err.Error()
+	}
+	if resp.Rcode != dns.RcodeSuccess || !hasType(resp.Answer, dns.TypeSOA) {
+		return false, fmt.Sprintf("expected SOA, got %s", dns.RcodeToString[resp.Rcode])
+	}
+	return true, ""
+}
+
+func ednsQuery(zone string, qtype uint16) *dns.Msg {
+	msg := &dns.Msg{}
+	msg.SetQuestion(zone, qtype)
+	msg.RecursionDesired = false
+	msg.SetEdns0(4096, false)
+	return msg
+}
+
+// ednsExchange sends msg exactly as built, without the TCP fallback of
+// exchangeWithFallback, since the probes test each transport on its own.
+func ednsExchange(ctx context.Context, network, server string, msg *dns.Msg) (*dns.Msg, error) {
+	client := &dns.Client{Net: network, Timeout: authoritativeQueryTimeout}
+	resp, _, err := client.ExchangeContext(ctx, msg, server)
+	return resp, err
+}
+
+func hasType(rrs []dns.RR, qtype uint16) bool {
+	for _, rr := range rrs {
+		if rr.Header().Rrtype == qtype {
+			return true
+		}
+	}
+	return false
+}
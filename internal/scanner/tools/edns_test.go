@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"encoding/base64"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// ednsHandler serves the SOA and a DNSKEY RRset too large for 512 bytes for
+// example.test. With edns set it follows RFC 6891; without it, it behaves
+// like a pre-EDNS server. Without tcp it refuses every query over TCP.
+func ednsHandler(t *testing.T, edns, tcp bool) dns.HandlerFunc {
+	t.Helper()
+
+	soa, err := dns.NewRR("example.test. 300 IN SOA ns1.example.test. hostmaster.example.test. 1 3600 600 86400 300")
+	if err != nil {
+		t.Fatalf("Invalid test record: %v", err)
+	}
+	var keys []dns.RR
+	for i := range 3 {
+		keys = append(keys, &dns.DNSKEY{
+			Hdr:       dns.RR_Header{Name: "example.test.", Rrtype: dns.TypeDNSKEY, Class: dns.ClassINET, Ttl: 300},
+			Flags:     257,
+			Protocol:  3,
+			Algorithm: dns.RSASHA256,
+			PublicKey: base64.StdEncoding.EncodeToString([]byte(strings.Repeat(string(rune('a'+i)), 260))),
+		})
+	}
+
+	return func(w dns.ResponseWriter, r *dns.Msg) {
+		m := &dns.Msg{}
+		m.SetReply(r)
+		m.Authoritative = true
+
+		isTCP := w.RemoteAddr().Network() == "tcp"
+		if isTCP && !tcp {
+			m.Rcode = dns.RcodeRefused
+			w.WriteMsg(m)
+			return
+		}
+
+		opt := r.IsEdns0()
+		if edns && opt != nil && opt.Version() != 0 {
+			m.Rcode = dns.RcodeBadVers
+			m.SetEdns0(4096, opt.Do())
+			w.WriteMsg(m)
+			return
+		}
+
+		switch r.Question[0].Qtype {
+		case dns.TypeSOA:
+			m.Answer = []dns.RR{dns.Copy(soa)}
+		case dns.TypeDNSKEY:
+			m.Answer = keys
+		}
+
+		size := dns.MinMsgSize
+		if edns && opt != nil {
+			m.SetEdns0(4096, opt.Do())
+			size = int(opt.UDPSize())
+		}
+		if !isTCP && m.Len() > size {
+			m.Truncated = true
+			m.Answer = nil
+		}
+		w.WriteMsg(m)
+	}
+}
+
+func TestCheckEDNS(t *testing.T) {
+	tests := []struct {
+		name   string
+		edns   bool
+		tcp    bool
+		failed []string
+	}{
+		{name: "compliant", edns: true, tcp: true},
+		{name: "no EDNS", edns: false, tcp: true, failed: []string{"edns", "edns1", "ednsopt", "do"}},
+		{name: "no TCP", edns: true, tcp: false, failed: []string{"bufsize", "tcp"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := startTestDNSServer(t, ednsHandler(t, tt.edns, tt.tcp))
+
+			results := CheckEDNS(context.Background(), server, "example.test.")
+			if len(results) != len(ednsProbes) {
+				t.Fatalf("Expected %d results, got %d", len(ednsProbes), len(results))
+			}
+
+			var failed []string
+			for _, result := range results {
+				if !result.Passed {
+					failed = append(failed, result.Test)
+					if result.Detail == "" {
+						t.Errorf("Expected detail for failed test %s", result.Test)
+					}
+				}
+			}
+			if strings.Join(failed, ",") != strings.Join(tt.failed, ",") {
+				t.Errorf("Expected failed tests %v, got %v (%+v)", tt.failed, failed, results)
+			}
+		})
+	}
+}
+
+func TestCheckEDNSCompliance(t *testing.T) {
+	addr := startTestDNSServer(t, ednsHandler(t, false, true))
+
+	_, port, _ := net.SplitHostPort(addr)
+	defer func(original string) { nameserverPort = original }(nameserverPort)
+	nameserverPort = port
+
+	analysis := models.NameserverAnalysis{
+		Servers: []models.NameserverCheck{
+			{Host: "ns1.example.test", Addresses: []string{"127.0.0.1"}, Reachable: true, Authoritative: true},
+			{Host: "ns2.example.test", Addresses: []string{"127.0.0.1"}, Reachable: true},
+		},
+	}
+	CheckEDNSCompliance(context.Background(), "example.test", &analysis)
+
+	if len(analysis.EDNSFailures) != 1 || analysis.EDNSFailures[0] != "ns1.example.test" {
+		t.Errorf("Expected only the authoritative server to fail, got %v", analysis.EDNSFailures)
+	}
+	if len(analysis.Servers[0].EDNS) != len(ednsProbes) {
+		t.Errorf("Expected %d results for ns1, got %d", len(ednsProbes), len(analysis.Servers[0].EDNS))
+	}
+	if len(analysis.Servers[1].EDNS) != 0 {
+		t.Error("Expected lame server to be skipped")
+	}
+}
+
+func TestCheckEDNSCompliance_ContextExpires(t *testing.T) {
+	// UDP is answered at once, TCP only after the scan has given up
+	compliant := ednsHandler(t, true, true)
+	addr := startTestDNSServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		if w.RemoteAddr().Network() == "tcp" {
+			time.Sleep(500 * time.Millisecond)
+		}
+		compliant(w, r)
+	}))
+
+	_, port, _ := net.SplitHostPort(addr)
+	defer func(original string) { nameserverPort = original }(nameserverPort)
+	nameserverPort = port
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	analysis := models.NameserverAnalysis{
+		Servers: []models.NameserverCheck{
+			{Host: "ns1.example.test", Addresses: []string{"127.0.0.1"}, Reachable: true, Authoritative: true},
+		},
+	}
+	CheckEDNSCompliance(ctx, "example.test", &analysis)
+
+	if len(analysis.EDNSFailures) != 0 {
+		t.Errorf("Expected no EDNS failures, got %v", analysis.EDNSFailures)
+	}
+	for _, result := range analysis.Servers[0].EDNS {
+		switch result.Test {
+		case "bufsize", "tcp":
+			if result.Passed || !result.Inconclusive {
+				t.Errorf("Expected %s to be inconclusive, got %+v", result.Test, result)
+			}
+		default:
+			if !result.Passed {
+				t.Errorf("Expected %s to pass, got %+v", result.Test, result)
+			}
+		}
+	}
+}
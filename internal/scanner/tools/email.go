@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"log/slog"
-	"net"
 	"strings"
 
 	"nsdigup/internal/logger"
@@ -14,12 +13,10 @@ import (
 // CheckEmailSecurity analyzes SPF and DMARC records for the given domain.
 // It identifies weak or missing email security configurations that could
 // allow email spoofing or phishing attacks.
-func CheckEmailSecurity(ctx context.Context, domain string) (models.EmailSec, error) {
+func CheckEmailSecurity(ctx context.Context, resolver Resolver, domain string) (models.EmailSec, error) {
 	emailSec := models.EmailSec{}
 
-	resolver := &net.Resolver{}
-
-	spfRecords, _ := resolver.LookupTXT(ctx, domain)
+	spfRecords, _ := lookupTXT(ctx, resolver, domain)
 	for _, txt := range spfRecords {
 		if strings.HasPrefix(txt, "v=spf1") {
 			emailSec.SPF = txt
@@ -30,7 +27,7 @@ func CheckEmailSecurity(ctx context.Context, domain string) (models.EmailSec, er
 		}
 	}
 
-	dmarcRecords, _ := resolver.LookupTXT(ctx, fmt.Sprintf("_dmarc.%s", domain))
+	dmarcRecords, _ := lookupTXT(ctx, resolver, fmt.Sprintf("_dmarc.%s", domain))
 	for _, txt := range dmarcRecords {
 		if strings.HasPrefix(txt, "v=DMARC1") {
 			if strings.Contains(txt, "p=none") {
@@ -0,0 +1,100 @@
+package tools
+
+import (
+	"slices"
+	"strings"
+
+	"nsdigup/pkg/models"
+)
+
+// eppStatusCodes are the EPP domain status codes (RFC 5731 section 2.3,
+// RFC 3915 section 3.1), keyed by their lowercase form without spaces so
+// that RDAP names (RFC 8056, "client transfer prohibited") match as well.
+var eppStatusCodes = map[string]string{}
+
+func init() {
+	for _, code := range []string{
+		"ok", "inactive",
+		"addPeriod", "autoRenewPeriod", "renewPeriod", "transferPeriod",
+		"redemptionPeriod", "pendingRestore",
+		"pendingCreate", "pendingDelete", "pendingRenew", "pendingTransfer", "pendingUpdate",
+		"clientDeleteProhibited", "clientHold", "clientRenewProhibited",
+		"clientTransferProhibited", "clientUpdateProhibited",
+		"serverDeleteProhibited", "serverHold", "serverRenewProhibited",
+		"serverTransferProhibited", "serverUpdateProhibited",
+	} {
+		eppStatusCodes[strings.ToLower(code)] = code
+	}
+	// RDAP calls the EPP "ok" status "active"
+	eppStatusCodes["active"] = "ok"
+}
+
+// registryLockCodes are set by the registry, not the registrar, when a
+// domain is under registry lock.
+var registryLockCodes = []string{"serverDeleteProhibited", "serverTransferProhibited", "serverUpdateProhibited"}
+
+// ParseDomainStatus turns the statuses from RDAP or WHOIS into EPP status
+// codes, and flags missing transfer protection, holds, and domains about to
+// be deleted. Without any status, or without any status that is an EPP code,
+// there is nothing to judge.
+func ParseDomainStatus(statuses []string) models.DomainStatus {
+	status := models.DomainStatus{}
+	recognised := false
+	for _, raw := range statuses {
+		if code := normalizeEPPStatus(raw); code != "" {
+			status.Codes = appendUnique(status.Codes, code)
+			_, ok := eppStatusCodes[strings.ToLower(code)]
+			recognised = recognised || ok
+		}
+	}
+	if len(status.Codes) == 0 {
+		return status
+	}
+	// Registries that don't use EPP codes, common for ccTLDs over WHOIS,
+	// give nothing to judge transfer protection or holds by
+	if !recognised {
+		status.Issues = append(status.Issues, "Unrecognised status values ("+strings.Join(status.Codes, ", ")+"), transfer protection could not be checked")
+		return status
+	}
+
+	has := func(code string) bool { return slices.Contains(status.Codes, code) }
+
+	status.TransferProtected = has("clientTransferProhibited") || has("serverTransferProhibited")
+	status.RegistryLock = true
+	for _, code := range registryLockCodes {
+		status.RegistryLock = status.RegistryLock && has(code)
+	}
+
+	if has("redemptionPeriod") || has("pendingRestore") {
+		status.Critical = append(status.Critical, "Domain is in its redemption period and will be deleted unless restored")
+	}
+	if has("pendingDelete") {
+		status.Critical = append(status.Critical, "Domain is pending deletion and will become available for registration")
+	}
+	if has("clientHold") || has("serverHold") {
+		status.Issues = append(status.Issues, "Domain is on hold and not published in DNS")
+	}
+	if !status.TransferProtected {
+		status.Issues = append(status.Issues, "Transfer not prohibited (no clientTransferProhibited), the domain can be transferred away")
+	}
+
+	return status
+}
+
+// normalizeEPPStatus returns the EPP spelling of a status. WHOIS usually
+// follows the code with an ICANN URL, which is dropped. Statuses that aren't
+// EPP codes are returned as given.
+func normalizeEPPStatus(raw string) string {
+	var words []string
+	for _, field := range strings.Fields(raw) {
+		if strings.Contains(field, "://") || strings.HasPrefix(field, "(") {
+			break
+		}
+		words = append(words, field)
+	}
+
+	if code, ok := eppStatusCodes[strings.ToLower(strings.Join(words, ""))]; ok {
+		return code
+	}
+	return strings.Join(words, " ")
+}
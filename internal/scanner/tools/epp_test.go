@@ -0,0 +1,118 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestNormalizeEPPStatus(t *testing.T) {
+	tests := []struct {
+		raw      string
+		expected string
+	}{
+		{"clientTransferProhibited", "clientTransferProhibited"},
+		{"clientTransferProhibited https://icann.org/epp#clientTransferProhibited", "clientTransferProhibited"},
+		{"serverDeleteProhibited (https://www.icann.org/epp#serverDeleteProhibited)", "serverDeleteProhibited"},
+		{"client transfer prohibited", "clientTransferProhibited"},
+		{"redemption period", "redemptionPeriod"},
+		{"active", "ok"},
+		{"clienttransferprohibited", "clientTransferProhibited"},
+		{"registrar-lock", "registrar-lock"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := normalizeEPPStatus(tt.raw); got != tt.expected {
+			t.Errorf("Expected %q for %q, got %q", tt.expected, tt.raw, got)
+		}
+	}
+}
+
+func TestParseDomainStatus(t *testing.T) {
+	tests := []struct {
+		name              string
+		statuses          []string
+		codes             string
+		transferProtected bool
+		registryLock      bool
+		critical          int
+		issues            int
+		issue             string
+	}{
+		{
+			name:     "no statuses",
+			statuses: nil,
+		},
+		{
+			name:              "transfer protected",
+			statuses:          []string{"client transfer prohibited", "client delete prohibited"},
+			codes:             "clientTransferProhibited,clientDeleteProhibited",
+			transferProtected: true,
+		},
+		{
+			name:     "unprotected",
+			statuses: []string{"ok https://icann.org/epp#ok"},
+			codes:    "ok",
+			issues:   1,
+		},
+		{
+			name:     "not EPP codes",
+			statuses: []string{"registrar-lock", "connect"},
+			codes:    "registrar-lock,connect",
+			issues:   1,
+			issue:    "Unrecognised status values",
+		},
+		{
+			name:     "EPP and other codes",
+			statuses: []string{"registrar-lock", "ok"},
+			codes:    "registrar-lock,ok",
+			issues:   1,
+			issue:    "Transfer not prohibited",
+		},
+		{
+			name: "registry lock",
+			statuses: []string{
+				"clientTransferProhibited https://icann.org/epp#clientTransferProhibited",
+				"serverDeleteProhibited https://icann.org/epp#serverDeleteProhibited",
+				"serverTransferProhibited https://icann.org/epp#serverTransferProhibited",
+				"serverUpdateProhibited https://icann.org/epp#serverUpdateProhibited",
+				"client transfer prohibited",
+			},
+			codes:             "clientTransferProhibited,serverDeleteProhibited,serverTransferProhibited,serverUpdateProhibited",
+			transferProtected: true,
+			registryLock:      true,
+		},
+		{
+			name:     "redemption and pending delete",
+			statuses: []string{"redemption period", "pending delete", "server hold"},
+			codes:    "redemptionPeriod,pendingDelete,serverHold",
+			critical: 2,
+			issues:   2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status := ParseDomainStatus(tt.statuses)
+
+			if codes := strings.Join(status.Codes, ","); codes != tt.codes {
+				t.Errorf("Expected codes %q, got %q", tt.codes, codes)
+			}
+			if status.TransferProtected != tt.transferProtected {
+				t.Errorf("Expected transfer protected %v, got %v", tt.transferProtected, status.TransferProtected)
+			}
+			if status.RegistryLock != tt.registryLock {
+				t.Errorf("Expected registry lock %v, got %v", tt.registryLock, status.RegistryLock)
+			}
+			if len(status.Critical) != tt.critical {
+				t.Errorf("Expected %d critical issues, got %v", tt.critical, status.Critical)
+			}
+			if len(status.Issues) != tt.issues {
+				t.Errorf("Expected %d issues, got %v", tt.issues, status.Issues)
+			}
+			if tt.issue != "" && (len(status.Issues) == 0 || !strings.HasPrefix(status.Issues[0], tt.issue)) {
+				t.Errorf("Expected issue starting with %q, got %v", tt.issue, status.Issues)
+			}
+		})
+	}
+}
@@ -19,7 +19,7 @@ type RedirectResult struct {
 }
 
 // CheckHTTPSRedirect tests if HTTP properly redirects to HTTPS
-func CheckHTTPSRedirect(ctx context.Context, domain string, timeout time.Duration) RedirectResult {
+func CheckHTTPSRedirect(ctx context.Context, resolver Resolver, domain string, timeout time.Duration) RedirectResult {
 	result := RedirectResult{
 		Enabled: false,
 	}
@@ -29,10 +29,14 @@ func CheckHTTPSRedirect(ctx context.Context, domain string, timeout time.Duratio
 	// Build HTTP URL (non-secure)
 	httpURL := fmt.Sprintf("http://%s", domain)
 
+	transport := newHTTPTransport(resolver, timeout)
+	defer transport.CloseIdleConnections()
+
 	// Create HTTP client that doesn't follow redirects automatically
 	// We want to inspect each redirect manually
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: transport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			// Don't follow redirects, we'll do it manually
 			return http.ErrUseLastResponse
@@ -131,11 +135,15 @@ func CheckHTTPSRedirect(ctx context.Context, domain string, timeout time.Duratio
 // CheckSecurityHeaders performs an HTTP request to the domain and checks for
 // security-related HTTP headers (HSTS, CSP, X-Frame-Options, etc.).
 // Returns a list of security issues found.
-func CheckHttpSecurityHeaders(ctx context.Context, domain string, timeout time.Duration) ([]string, error) {
+func CheckHttpSecurityHeaders(ctx context.Context, resolver Resolver, domain string, timeout time.Duration) ([]string, error) {
 	issues := []string{}
 
+	transport := newHTTPTransport(resolver, timeout)
+	defer transport.CloseIdleConnections()
+
 	client := &http.Client{
-		Timeout: timeout,
+		Timeout:   timeout,
+		Transport: transport,
 		CheckRedirect: func(req *http.Request, via []*http.Request) error {
 			if len(via) >= 3 {
 				return fmt.Errorf("too many redirects")
@@ -0,0 +1,162 @@
+package tools
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode"
+
+	"golang.org/x/net/idna"
+
+	"nsdigup/pkg/models"
+)
+
+// bundledConfusables maps characters to the ASCII character they are
+// commonly mistaken for, a subset of the Unicode confusables
+// (UTS #39 section 4) covering the scripts used in homograph attacks.
+//
+//go:embed data/confusables.json
+var bundledConfusables []byte
+
+var confusables = func() map[rune]rune {
+	var table map[string]string
+	if err := json.Unmarshal(bundledConfusables, &table); err != nil {
+		panic(fmt.Sprintf("invalid bundled confusables: %v", err))
+	}
+
+	confusables := make(map[rune]rune, len(table))
+	for from, to := range table {
+		confusables[[]rune(from)[0]] = []rune(to)[0]
+	}
+	return confusables
+}()
+
+// idnScripts are the scripts reported for a label, in the order they are
+// listed. Characters of other scripts are reported as "Other".
+var idnScripts = []string{
+	"Latin", "Cyrillic", "Greek", "Armenian", "Georgian", "Hebrew", "Arabic",
+	"Han", "Hiragana", "Katakana", "Hangul", "Bopomofo", "Thai", "Devanagari",
+	"Bengali", "Cherokee",
+}
+
+// allowedScriptMixes are the script combinations UTS #39 allows in a
+// "highly restrictive" label, besides a single script: Latin with the
+// scripts of Japanese, Chinese and Korean writing.
+var allowedScriptMixes = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// ToASCII converts a domain name to its A-label form with the UTS #46
+// mapping IDNA2008 lookups use, e.g. "Bücher.de" -> "xn--bcher-kva.de".
+func ToASCII(domain string) (string, error) {
+	return idna.Lookup.ToASCII(domain)
+}
+
+// CheckIDN describes an internationalized domain name and flags labels
+// that look like homographs: labels mixing scripts, and labels made of
+// characters confusable with ASCII. It returns nil for plain ASCII names.
+func CheckIDN(domain string) *models.IDN {
+	domain = normalizeDomain(domain)
+
+	ascii, err := ToASCII(domain)
+	if err != nil {
+		return nil
+	}
+	unicodeName, err := idna.Lookup.ToUnicode(ascii)
+	if err != nil || unicodeName == ascii {
+		return nil
+	}
+
+	result := &models.IDN{ASCII: ascii, Unicode: unicodeName}
+
+	lookalike := make([]string, 0, strings.Count(unicodeName, ".")+1)
+	confusable := false
+	for _, label := range strings.Split(unicodeName, ".") {
+		scripts := labelScripts(label)
+		for _, script := range scripts {
+			result.Scripts = appendUnique(result.Scripts, script)
+		}
+		if len(scripts) > 1 && !allowedScriptMix(scripts) {
+			result.Issues = append(result.Issues,
+				fmt.Sprintf("Label %q mixes %s scripts", label, strings.Join(scripts, " and ")))
+		}
+
+		skeleton, ok := asciiSkeleton(label)
+		if ok && skeleton != label {
+			confusable = true
+			result.Issues = append(result.Issues,
+				fmt.Sprintf("Label %q looks like %q", label, skeleton))
+		}
+		lookalike = append(lookalike, skeleton)
+	}
+
+	sortScripts(result.Scripts)
+	if confusable {
+		result.Lookalike = strings.Join(lookalike, ".")
+	}
+
+	return result
+}
+
+// labelScripts returns the scripts of the characters in a label. Digits,
+// hyphens and combining marks belong to every script and are skipped.
+func labelScripts(label string) []string {
+	var scripts []string
+	for _, r := range label {
+		if unicode.In(r, unicode.Common, unicode.Inherited) {
+			continue
+		}
+		script := "Other"
+		for _, name := range idnScripts {
+			if unicode.Is(unicode.Scripts[name], r) {
+				script = name
+				break
+			}
+		}
+		scripts = appendUnique(scripts, script)
+	}
+	sortScripts(scripts)
+	return scripts
+}
+
+// sortScripts orders scripts as listed in idnScripts, with "Other" last.
+func sortScripts(scripts []string) {
+	order := func(script string) int {
+		if i := slices.Index(idnScripts, script); i >= 0 {
+			return i
+		}
+		return len(idnScripts)
+	}
+	slices.SortFunc(scripts, func(a, b string) int { return order(a) - order(b) })
+}
+
+func allowedScriptMix(scripts []string) bool {
+	for _, allowed := range allowedScriptMixes {
+		if !slices.ContainsFunc(scripts, func(script string) bool { return !slices.Contains(allowed, script) }) {
+			return true
+		}
+	}
+	return false
+}
+
+// asciiSkeleton replaces every confusable character of a label with the
+// ASCII character it resembles. It reports whether the result is all ASCII,
+// i.e. the label could pass for an ASCII name.
+func asciiSkeleton(label string) (string, bool) {
+	var b strings.Builder
+	ascii := true
+	for _, r := range label {
+		if to, ok := confusables[r]; ok {
+			r = to
+		}
+		if r > unicode.MaxASCII {
+			ascii = false
+		}
+		b.WriteRune(r)
+	}
+	return b.String(), ascii
+}
@@ -0,0 +1,117 @@
+package tools
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestToASCII(t *testing.T) {
+	tests := []struct {
+		domain   string
+		expected string
+	}{
+		{"example.com", "example.com"},
+		{"bücher.de", "xn--bcher-kva.de"},
+		{"Bücher.DE", "xn--bcher-kva.de"},
+		{"faß.de", "xn--fa-hia.de"},
+		{"xn--bcher-kva.de", "xn--bcher-kva.de"},
+		{"例え.jp", "xn--r8jz45g.jp"},
+	}
+
+	for _, tt := range tests {
+		got, err := ToASCII(tt.domain)
+		if err != nil {
+			t.Errorf("Unexpected error for %q: %v", tt.domain, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("Expected %q for %q, got %q", tt.expected, tt.domain, got)
+		}
+	}
+
+	if _, err := ToASCII("-invalid-.com"); err == nil {
+		t.Error("Expected label with leading hyphen to be rejected")
+	}
+}
+
+func TestCheckIDN(t *testing.T) {
+	tests := []struct {
+		name      string
+		domain    string
+		unicode   string
+		scripts   string
+		lookalike string
+		issues    int
+	}{
+		{
+			name:   "ASCII",
+			domain: "example.com",
+		},
+		{
+			name:    "Latin with diacritics",
+			domain:  "xn--bcher-kva.de",
+			unicode: "bücher.de",
+			scripts: "Latin",
+		},
+		{
+			name:    "Japanese",
+			domain:  "例えテスト.jp",
+			unicode: "例えテスト.jp",
+			scripts: "Latin,Han,Hiragana,Katakana",
+		},
+		{
+			name:      "whole-script Cyrillic",
+			domain:    "аррӏе.com",
+			unicode:   "аррӏе.com",
+			scripts:   "Latin,Cyrillic",
+			lookalike: "apple.com",
+			issues:    1,
+		},
+		{
+			name:      "mixed Latin and Cyrillic",
+			domain:    "pаypal.com",
+			unicode:   "pаypal.com",
+			scripts:   "Latin,Cyrillic",
+			lookalike: "paypal.com",
+			issues:    2,
+		},
+		{
+			name:    "mixed but not confusable",
+			domain:  "shopжук.com",
+			unicode: "shopжук.com",
+			scripts: "Latin,Cyrillic",
+			issues:  1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			idn := CheckIDN(tt.domain)
+			if tt.unicode == "" {
+				if idn != nil {
+					t.Errorf("Expected no IDN for %q, got %+v", tt.domain, idn)
+				}
+				return
+			}
+			if idn == nil {
+				t.Fatalf("Expected IDN for %q", tt.domain)
+			}
+
+			if idn.Unicode != tt.unicode {
+				t.Errorf("Expected Unicode form %q, got %q", tt.unicode, idn.Unicode)
+			}
+			if !strings.HasPrefix(idn.ASCII, "xn--") {
+				t.Errorf("Expected A-label, got %q", idn.ASCII)
+			}
+			if scripts := strings.Join(idn.Scripts, ","); scripts != tt.scripts {
+				t.Errorf("Expected scripts %q, got %q", tt.scripts, scripts)
+			}
+			if idn.Lookalike != tt.lookalike {
+				t.Errorf("Expected lookalike %q, got %q", tt.lookalike, idn.Lookalike)
+			}
+			if len(idn.Issues) != tt.issues {
+				t.Errorf("Expected %d issues, got %v", tt.issues, idn.Issues)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/oschwald/maxminddb-golang"
+
+	"nsdigup/pkg/models"
+)
+
+// NetworkDB answers which network an IP address belongs to from local MMDB
+// files, such as MaxMind GeoLite2 ASN/Country or IPinfo databases. Lookups
+// never leave the machine. A nil NetworkDB finds nothing.
+type NetworkDB struct {
+	readers []*maxminddb.Reader
+}
+
+// OpenNetworkDB opens every MMDB file. Results from later files fill in the
+// fields earlier ones lack, so an ASN database can be combined with a
+// country database.
+func OpenNetworkDB(paths []string) (*NetworkDB, error) {
+	db := &NetworkDB{}
+	for _, path := range paths {
+		reader, err := maxminddb.Open(path)
+		if err != nil {
+			db.Close()
+			return nil, fmt.Errorf("failed to open MMDB file %s: %w", path, err)
+		}
+		db.readers = append(db.readers, reader)
+	}
+	return db, nil
+}
+
+// Close releases the underlying database files.
+func (db *NetworkDB) Close() error {
+	if db == nil {
+		return nil
+	}
+	var firstErr error
+	for _, reader := range db.readers {
+		if err := reader.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// Lookup returns the network of ip, and whether any database knew it.
+func (db *NetworkDB) Lookup(ip string) (models.Network, bool) {
+	var network models.Network
+	if db == nil {
+		return network, false
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return network, false
+	}
+
+	found := false
+	for _, reader := range db.readers {
+		var record map[string]any
+		prefix, ok, err := reader.LookupNetwork(parsed, &record)
+		if err != nil || !ok {
+			continue
+		}
+		found = true
+		mergeNetwork(&network, record, prefix)
+	}
+
+	return network, found
+}
+
+// mergeNetwork fills the empty fields of network from an MMDB record, which
+// may use either the MaxMind or the IPinfo field names.
+func mergeNetwork(network *models.Network, record map[string]any, prefix *net.IPNet) {
+	var asn uint32
+	switch value := record["autonomous_system_number"].(type) {
+	case uint64:
+		asn = uint32(value)
+	default:
+		// IPinfo stores ASNs as "AS15169"
+		if s, ok := record["asn"].(string); ok {
+			if n, err := strconv.ParseUint(strings.TrimPrefix(strings.ToUpper(s), "AS"), 10, 32); err == nil {
+				asn = uint32(n)
+			}
+		}
+	}
+
+	// Only ASN records describe the announced prefix, country databases
+	// split networks along other lines
+	if network.ASN == 0 && asn != 0 {
+		network.ASN = asn
+		if prefix != nil {
+			network.Prefix = prefix.String()
+		}
+	}
+
+	if network.Organization == "" {
+		for _, key := range []string{"autonomous_system_organization", "as_name"} {
+			if org, ok := record[key].(string); ok && org != "" {
+				network.Organization = org
+				break
+			}
+		}
+	}
+
+	if network.Country == "" {
+		switch country := record["country"].(type) {
+		case map[string]any:
+			if code, ok := country["iso_code"].(string); ok {
+				network.Country = code
+			}
+		case string:
+			network.Country = country
+		}
+		if code, ok := record["country_code"].(string); ok && network.Country == "" {
+			network.Country = code
+		}
+	}
+}
+
+// EnrichAddresses annotates every address with its network.
+func EnrichAddresses(db *NetworkDB, addresses []models.Address) {
+	for i := range addresses {
+		if network, ok := db.Lookup(addresses[i].IP); ok {
+			addresses[i].Network = &network
+		}
+	}
+}
+
+// EnrichNameservers annotates every nameserver with the networks its
+// addresses belong to.
+func EnrichNameservers(db *NetworkDB, analysis *models.NameserverAnalysis) {
+	for i := range analysis.Servers {
+		server := &analysis.Servers[i]
+		for _, ip := range server.Addresses {
+			if network, ok := db.Lookup(ip); ok {
+				server.Networks = append(server.Networks, network)
+			}
+		}
+	}
+}
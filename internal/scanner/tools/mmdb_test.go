@@ -0,0 +1,206 @@
+package tools
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"os"
+	"path/filepath"
+	"sort"
+	"testing"
+
+	"nsdigup/pkg/models"
+)
+
+// writeTestMMDB writes a minimal IPv4 MaxMind DB with one record per network.
+func writeTestMMDB(t *testing.T, records map[string]map[string]any) string {
+	t.Helper()
+
+	type record struct {
+		node, data int // node index or data offset, -1 when unset
+	}
+	nodes := [][2]record{{{-1, -1}, {-1, -1}}}
+
+	var data bytes.Buffer
+	for cidr, value := range records {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatalf("Invalid test network %q: %v", cidr, err)
+		}
+		ones, _ := network.Mask.Size()
+		offset := data.Len()
+		encodeMMDBValue(&data, value)
+
+		node := 0
+		for i := range ones {
+			bit := (network.IP.To4()[i/8] >> (7 - i%8)) & 1
+			if i == ones-1 {
+				nodes[node][bit] = record{node: -1, data: offset}
+				break
+			}
+			if nodes[node][bit].node < 0 {
+				nodes = append(nodes, [2]record{{-1, -1}, {-1, -1}})
+				nodes[node][bit] = record{node: len(nodes) - 1, data: -1}
+			}
+			node = nodes[node][bit].node
+		}
+	}
+
+	var file bytes.Buffer
+	nodeCount := uint32(len(nodes))
+	for _, node := range nodes {
+		for _, r := range node {
+			value := nodeCount // empty
+			switch {
+			case r.node >= 0:
+				value = uint32(r.node)
+			case r.data >= 0:
+				value = nodeCount + 16 + uint32(r.data)
+			}
+			binary.Write(&file, binary.BigEndian, value)
+		}
+	}
+	file.Write(make([]byte, 16))
+	file.Write(data.Bytes())
+	file.WriteString("\xAB\xCD\xEFMaxMind.com")
+	encodeMMDBValue(&file, map[string]any{
+		"node_count":                  nodeCount,
+		"record_size":                 uint16(32),
+		"ip_version":                  uint16(4),
+		"binary_format_major_version": uint16(2),
+		"binary_format_minor_version": uint16(0),
+		"database_type":               "nsdigup-test",
+		"build_epoch":                 uint32(1700000000),
+	})
+
+	path := filepath.Join(t.TempDir(), "test.mmdb")
+	if err := os.WriteFile(path, file.Bytes(), 0o600); err != nil {
+		t.Fatalf("Failed to write test MMDB: %v", err)
+	}
+	return path
+}
+
+// encodeMMDBValue encodes the few MMDB data types the tests need.
+func encodeMMDBValue(buf *bytes.Buffer, value any) {
+	uintBytes := func(v uint64, size int) []byte {
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, v)
+		b = b[8-size:]
+		return bytes.TrimLeft(b, "\x00")
+	}
+
+	switch v := value.(type) {
+	case string:
+		// Sizes from 29 on spill into the next byte
+		if len(v) < 29 {
+			buf.WriteByte(2<<5 | byte(len(v)))
+		} else {
+			buf.WriteByte(2<<5 | 29)
+			buf.WriteByte(byte(len(v) - 29))
+		}
+		buf.WriteString(v)
+	case uint16:
+		b := uintBytes(uint64(v), 2)
+		buf.WriteByte(5<<5 | byte(len(b)))
+		buf.Write(b)
+	case uint32:
+		b := uintBytes(uint64(v), 4)
+		buf.WriteByte(6<<5 | byte(len(b)))
+		buf.Write(b)
+	case map[string]any:
+		buf.WriteByte(7<<5 | byte(len(v)))
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			encodeMMDBValue(buf, key)
+			encodeMMDBValue(buf, v[key])
+		}
+	}
+}
+
+func TestNetworkDB_Lookup(t *testing.T) {
+	asnDB := writeTestMMDB(t, map[string]map[string]any{
+		"192.0.2.0/24": {"autonomous_system_number": uint32(64500), "autonomous_system_organization": "Example Hosting"},
+	})
+	countryDB := writeTestMMDB(t, map[string]map[string]any{
+		"192.0.2.0/25": {"country": map[string]any{"iso_code": "NL"}},
+	})
+	ipinfoDB := writeTestMMDB(t, map[string]map[string]any{
+		"198.51.100.0/24": {"asn": "AS64501", "as_name": "Other Network", "country": "DE"},
+	})
+
+	db, err := OpenNetworkDB([]string{asnDB, countryDB, ipinfoDB})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	tests := []struct {
+		ip    string
+		want  models.Network
+		found bool
+	}{
+		{ip: "192.0.2.10", want: models.Network{ASN: 64500, Organization: "Example Hosting", Prefix: "192.0.2.0/24", Country: "NL"}, found: true},
+		{ip: "192.0.2.200", want: models.Network{ASN: 64500, Organization: "Example Hosting", Prefix: "192.0.2.0/24"}, found: true},
+		{ip: "198.51.100.7", want: models.Network{ASN: 64501, Organization: "Other Network", Prefix: "198.51.100.0/24", Country: "DE"}, found: true},
+		{ip: "203.0.113.1", found: false},
+		{ip: "2001:db8::1", found: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			got, found := db.Lookup(tt.ip)
+			if found != tt.found || got != tt.want {
+				t.Errorf("Expected %+v (found %v), got %+v (found %v)", tt.want, tt.found, got, found)
+			}
+		})
+	}
+}
+
+func TestNetworkDB_OpenInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "broken.mmdb")
+	if err := os.WriteFile(path, []byte("not a database"), 0o600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if _, err := OpenNetworkDB([]string{path}); err == nil {
+		t.Error("Expected error for invalid MMDB file")
+	}
+}
+
+func TestEnrich_NilDB(t *testing.T) {
+	var db *NetworkDB
+
+	addresses := []models.Address{{IP: "192.0.2.10"}}
+	EnrichAddresses(db, addresses)
+	if addresses[0].Network != nil {
+		t.Error("Expected no network without a database")
+	}
+}
+
+func TestEnrichNameservers(t *testing.T) {
+	path := writeTestMMDB(t, map[string]map[string]any{
+		"192.0.2.0/24": {"autonomous_system_number": uint32(64500), "autonomous_system_organization": "Example DNS"},
+	})
+
+	db, err := OpenNetworkDB([]string{path})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	defer db.Close()
+
+	analysis := models.NameserverAnalysis{
+		Servers: []models.NameserverCheck{
+			{Host: "ns1.example.com", Addresses: []string{"192.0.2.53", "203.0.113.53"}},
+		},
+	}
+	EnrichNameservers(db, &analysis)
+
+	networks := analysis.Servers[0].Networks
+	if len(networks) != 1 || networks[0].ASN != 64500 {
+		t.Errorf("Expected one known network, got %+v", networks)
+	}
+}
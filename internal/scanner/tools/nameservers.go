@@ -0,0 +1,288 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// authoritativeQueryTimeout bounds every query sent directly to a nameserver
+const authoritativeQueryTimeout = 2 * time.Second
+
+// nameserverPort is the port authoritative nameservers are queried on,
+// overridden in tests to reach a local server
+var nameserverPort = "53"
+
+// recursionProbeNames are names unrelated to the scanned zone, asked with RD
+// set to find nameservers that recurse for anyone. The second one is used
+// when the first falls inside the scanned zone.
+var recursionProbeNames = []string{"www.iana.org.", "www.example.com."}
+
+// CheckNameserverConsistency queries every authoritative nameserver of the
+// domain directly for SOA and NS, and compares their answers with each other
+// and with the delegation held by the parent zone. It reports serial
+// mismatches, lame delegations, unreachable servers and NS set differences.
+func CheckNameserverConsistency(ctx context.Context, resolver Resolver, domain string, nameservers []string) models.NameserverAnalysis {
+	analysis := models.NameserverAnalysis{}
+	if len(nameservers) == 0 {
+		return analysis
+	}
+
+	zone := dns.Fqdn(normalizeDomain(domain))
+
+	// Query every nameserver in parallel, keeping the input order
+	analysis.Servers = make([]models.NameserverCheck, len(nameservers))
+	var wg sync.WaitGroup
+	for i, host := range nameservers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			analysis.Servers[i] = checkNameserver(ctx, resolver, zone, host)
+		}()
+	}
+
+	parentNS, err := lookupDelegation(ctx, resolver, zone)
+	if err != nil {
+		analysis.Error = fmt.Sprintf("parent delegation lookup failed: %v", err)
+	}
+	analysis.ParentNS = parentNS
+
+	wg.Wait()
+
+	summarizeNameservers(&analysis, nameservers)
+	return analysis
+}
+
+// checkNameserver resolves a nameserver's addresses and asks it for the
+// zone's SOA and NS records, trying each address until one answers.
+func checkNameserver(ctx context.Context, resolver Resolver, zone, host string) models.NameserverCheck {
+	check := models.NameserverCheck{Host: host}
+
+	addrs, err := lookupAddresses(ctx, resolver, host)
+	if err != nil || len(addrs) == 0 {
+		check.Error = fmt.Sprintf("cannot resolve nameserver address: %v", err)
+		return check
+	}
+	check.Addresses = addrs
+
+	var lastErr error
+	for _, ip := range addrs {
+		server := net.JoinHostPort(ip, nameserverPort)
+		soa, err := queryAuthoritative(ctx, server, zone, dns.TypeSOA)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+
+		check.Reachable = true
+		check.OpenResolver = checkOpenRecursion(ctx, server, zone)
+
+		for _, ans := range soa.Answer {
+			if rr, ok := ans.(*dns.SOA); ok {
+				check.Serial = rr.Serial
+				check.Authoritative = soa.Authoritative && soa.Rcode == dns.RcodeSuccess
+			}
+		}
+
+		if !check.Authoritative {
+			check.Error = fmt.Sprintf("not authoritative for %s (%s)", strings.TrimSuffix(zone, "."), dns.RcodeToString[soa.Rcode])
+			return check
+		}
+
+		if ns, err := queryAuthoritative(ctx, server, zone, dns.TypeNS); err == nil {
+			check.NS = nsHosts(ns.Answer, zone)
+		}
+		return check
+	}
+
+	check.Error = fmt.Sprintf("no response: %v", lastErr)
+	return check
+}
+
+// checkOpenRecursion sends a recursive query for a name outside zone and
+// reports whether the server resolved it, which makes it usable for
+// DNS amplification attacks.
+func checkOpenRecursion(ctx context.Context, server, zone string) bool {
+	name := recursionProbeNames[0]
+	if dns.IsSubDomain(zone, name) {
+		name = recursionProbeNames[1]
+	}
+
+	msg := &dns.Msg{}
+	msg.SetQuestion(name, dns.TypeA)
+	msg.RecursionDesired = true
+
+	client := &dns.Client{Timeout: authoritativeQueryTimeout}
+	resp, err := exchangeWithFallback(ctx, client, msg, server)
+	if err != nil {
+		return false
+	}
+
+	return resp.RecursionAvailable && resp.Rcode == dns.RcodeSuccess && len(resp.Answer) > 0
+}
+
+// lookupDelegation asks the parent zone's nameservers which NS records they
+// hand out for zone (the delegation, found in the referral's authority section).
+func lookupDelegation(ctx context.Context, resolver Resolver, zone string) ([]string, error) {
+	parent := parentZone(zone)
+
+	parentServers, err := GetNameservers(ctx, resolver, parent)
+	if err != nil {
+		return nil, err
+	}
+
+	// A few parent servers are plenty to find one that answers
+	if len(parentServers) > 3 {
+		parentServers = parentServers[:3]
+	}
+
+	for _, host := range parentServers {
+		addrs, err := lookupAddresses(ctx, resolver, host)
+		if err != nil {
+			continue
+		}
+
+		for _, ip := range addrs {
+			resp, err := queryAuthoritative(ctx, net.JoinHostPort(ip, nameserverPort), zone, dns.TypeNS)
+			if err != nil {
+				continue
+			}
+
+			// Parents that also serve the child answer directly
+			delegation := nsHosts(append(resp.Ns, resp.Answer...), zone)
+			if len(delegation) == 0 {
+				return nil, fmt.Errorf("%s has no delegation for %s", parent, zone)
+			}
+			return delegation, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no nameserver of %s answered", parent)
+}
+
+// summarizeNameservers derives the consistency verdicts from the per-server results.
+func summarizeNameservers(analysis *models.NameserverAnalysis, nameservers []string) {
+	serials := make(map[uint32]bool)
+	childNS := make(map[string]bool)
+
+	for _, server := range analysis.Servers {
+		if server.OpenResolver {
+			analysis.OpenResolvers = append(analysis.OpenResolvers, server.Host)
+		}
+
+		switch {
+		case !server.Reachable:
+			analysis.Unreachable = append(analysis.Unreachable, server.Host)
+		case !server.Authoritative:
+			analysis.LameDelegations = append(analysis.LameDelegations, server.Host)
+		default:
+			serials[server.Serial] = true
+			for _, ns := range server.NS {
+				childNS[ns] = true
+			}
+		}
+	}
+
+	analysis.SerialMismatch = len(serials) > 1
+
+	// Fall back to the resolver's view when no server gave us its NS set
+	if len(childNS) == 0 {
+		for _, ns := range nameservers {
+			childNS[normalizeHost(ns)] = true
+		}
+	}
+	analysis.ChildNS = sortedKeys(childNS)
+
+	if len(analysis.ParentNS) > 0 {
+		parentNS := make(map[string]bool)
+		for _, ns := range analysis.ParentNS {
+			parentNS[ns] = true
+			if !childNS[ns] {
+				analysis.OnlyAtParent = append(analysis.OnlyAtParent, ns)
+			}
+		}
+		for _, ns := range analysis.ChildNS {
+			if !parentNS[ns] {
+				analysis.OnlyAtChild = append(analysis.OnlyAtChild, ns)
+			}
+		}
+	}
+}
+
+// queryAuthoritative sends a non-recursive query directly to server (host:port).
+func queryAuthoritative(ctx context.Context, server, name string, qtype uint16) (*dns.Msg, error) {
+	msg := &dns.Msg{}
+	msg.SetQuestion(dns.Fqdn(name), qtype)
+	msg.RecursionDesired = false
+	msg.SetEdns0(4096, false)
+
+	client := &dns.Client{Timeout: authoritativeQueryTimeout}
+	return exchangeWithFallback(ctx, client, msg, server)
+}
+
+// lookupAddresses resolves all IPv4 and IPv6 addresses of host.
+func lookupAddresses(ctx context.Context, resolver Resolver, host string) ([]string, error) {
+	var addrs []string
+	var lastErr error
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA} {
+		resp, err := lookup(ctx, resolver, host, qtype)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		for _, ans := range resp.Answer {
+			switch rr := ans.(type) {
+			case *dns.A:
+				addrs = append(addrs, rr.A.String())
+			case *dns.AAAA:
+				addrs = append(addrs, rr.AAAA.String())
+			}
+		}
+	}
+
+	if len(addrs) == 0 && lastErr != nil {
+		return nil, lastErr
+	}
+	return addrs, nil
+}
+
+// nsHosts extracts the normalized, sorted NS targets owned by zone.
+func nsHosts(rrs []dns.RR, zone string) []string {
+	hosts := make(map[string]bool)
+	for _, rr := range rrs {
+		if ns, ok := rr.(*dns.NS); ok && strings.EqualFold(ns.Hdr.Name, zone) {
+			hosts[normalizeHost(ns.Ns)] = true
+		}
+	}
+	return sortedKeys(hosts)
+}
+
+// parentZone returns the zone directly above zone ("example.com." -> "com.").
+func parentZone(zone string) string {
+	labels := dns.SplitDomainName(zone)
+	if len(labels) <= 1 {
+		return "."
+	}
+	return dns.Fqdn(strings.Join(labels[1:], "."))
+}
+
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSuffix(host, "."))
+}
+
+func sortedKeys(set map[string]bool) []string {
+	keys := make([]string, 0, len(set))
+	for key := range set {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+func TestCheckNameserverConsistency(t *testing.T) {
+	// One local server plays resolver, parent and both child nameservers
+	addr := startTestDNSServer(t, testZone(t,
+		"test. 300 IN NS ns.test.",
+		"ns.test. 300 IN A 127.0.0.1",
+		"example.test. 300 IN SOA ns1.example.test. hostmaster.example.test. 2024010101 3600 600 86400 300",
+		"example.test. 300 IN NS ns1.example.test.",
+		"example.test. 300 IN NS ns2.example.test.",
+		"ns1.example.test. 300 IN A 127.0.0.1",
+		"ns2.example.test. 300 IN A 127.0.0.1",
+	))
+
+	_, port, _ := net.SplitHostPort(addr)
+	defer func(original string) { nameserverPort = original }(nameserverPort)
+	nameserverPort = port
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+	analysis := CheckNameserverConsistency(context.Background(), resolver, "example.test",
+		[]string{"ns1.example.test", "ns2.example.test"})
+
+	if analysis.Error != "" {
+		t.Fatalf("Unexpected error: %s", analysis.Error)
+	}
+
+	if len(analysis.Servers) != 2 {
+		t.Fatalf("Expected 2 servers checked, got %d", len(analysis.Servers))
+	}
+
+	for _, server := range analysis.Servers {
+		if !server.Reachable || !server.Authoritative {
+			t.Errorf("Expected %s to be reachable and authoritative: %+v", server.Host, server)
+		}
+		if server.Serial != 2024010101 {
+			t.Errorf("Expected serial 2024010101 from %s, got %d", server.Host, server.Serial)
+		}
+	}
+
+	want := []string{"ns1.example.test", "ns2.example.test"}
+	if !reflect.DeepEqual(analysis.ParentNS, want) {
+		t.Errorf("Expected parent NS %v, got %v", want, analysis.ParentNS)
+	}
+	if !reflect.DeepEqual(analysis.ChildNS, want) {
+		t.Errorf("Expected child NS %v, got %v", want, analysis.ChildNS)
+	}
+
+	if analysis.SerialMismatch || len(analysis.LameDelegations) > 0 || len(analysis.OnlyAtParent) > 0 {
+		t.Errorf("Expected consistent nameservers, got %+v", analysis)
+	}
+}
+
+func TestSummarizeNameservers(t *testing.T) {
+	analysis := models.NameserverAnalysis{
+		Servers: []models.NameserverCheck{
+			{Host: "ns1.example.com", Reachable: true, Authoritative: true, Serial: 10, NS: []string{"ns1.example.com", "ns2.example.com", "ns3.example.com"}},
+			{Host: "ns2.example.com", Reachable: true, Authoritative: true, Serial: 11, NS: []string{"ns1.example.com", "ns2.example.com", "ns3.example.com"}},
+			{Host: "ns3.example.com", Reachable: true, OpenResolver: true},
+			{Host: "ns4.example.com"},
+		},
+		ParentNS: []string{"ns1.example.com", "ns2.example.com", "ns4.example.com"},
+	}
+
+	summarizeNameservers(&analysis, []string{"ns1.example.com", "ns2.example.com", "ns3.example.com", "ns4.example.com"})
+
+	if !analysis.SerialMismatch {
+		t.Error("Expected serial mismatch")
+	}
+
+	if !reflect.DeepEqual(analysis.LameDelegations, []string{"ns3.example.com"}) {
+		t.Errorf("Expected ns3 to be lame, got %v", analysis.LameDelegations)
+	}
+
+	if !reflect.DeepEqual(analysis.OpenResolvers, []string{"ns3.example.com"}) {
+		t.Errorf("Expected ns3 to be an open resolver, got %v", analysis.OpenResolvers)
+	}
+
+	if !reflect.DeepEqual(analysis.Unreachable, []string{"ns4.example.com"}) {
+		t.Errorf("Expected ns4 to be unreachable, got %v", analysis.Unreachable)
+	}
+
+	if !reflect.DeepEqual(analysis.OnlyAtParent, []string{"ns4.example.com"}) {
+		t.Errorf("Expected ns4 only at parent, got %v", analysis.OnlyAtParent)
+	}
+
+	if !reflect.DeepEqual(analysis.OnlyAtChild, []string{"ns3.example.com"}) {
+		t.Errorf("Expected ns3 only at child, got %v", analysis.OnlyAtChild)
+	}
+}
+
+func TestCheckOpenRecursion(t *testing.T) {
+	var mu sync.Mutex
+	var asked string
+	recursive := startTestDNSServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		mu.Lock()
+		asked = r.Question[0].Name
+		mu.Unlock()
+		m := &dns.Msg{}
+		m.SetReply(r)
+		m.RecursionAvailable = r.RecursionDesired
+		rr, _ := dns.NewRR(r.Question[0].Name + " 300 IN A 192.0.2.1")
+		m.Answer = append(m.Answer, rr)
+		w.WriteMsg(m)
+	}))
+
+	refusing := startTestDNSServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		m := &dns.Msg{}
+		m.SetReply(r)
+		m.RecursionAvailable = true
+		m.Rcode = dns.RcodeRefused
+		w.WriteMsg(m)
+	}))
+
+	authoritative := startTestDNSServer(t, testZone(t, "example.test. 300 IN A 192.0.2.10"))
+
+	ctx := context.Background()
+
+	if !checkOpenRecursion(ctx, recursive, "example.test.") {
+		t.Error("Expected recursive server to be flagged")
+	}
+
+	if checkOpenRecursion(ctx, refusing, "example.test.") {
+		t.Error("Expected server refusing recursion not to be flagged")
+	}
+
+	if checkOpenRecursion(ctx, authoritative, "example.test.") {
+		t.Error("Expected authoritative-only server not to be flagged")
+	}
+
+	// The probe name must never fall inside the scanned zone
+	checkOpenRecursion(ctx, recursive, "iana.org.")
+	mu.Lock()
+	defer mu.Unlock()
+	if dns.IsSubDomain("iana.org.", asked) {
+		t.Errorf("Expected probe outside the scanned zone, asked %s", asked)
+	}
+}
+
+func TestParentZone(t *testing.T) {
+	tests := map[string]string{
+		"www.example.com.": "example.com.",
+		"example.com.":     "com.",
+		"com.":             ".",
+	}
+
+	for zone, want := range tests {
+		if got := parentZone(zone); got != want {
+			t.Errorf("parentZone(%q): expected %q, got %q", zone, want, got)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package tools
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"nsdigup/pkg/models"
+)
+
+// CheckNameserverDomains looks up the registration of every domain the
+// nameservers are under, other than the target's own. A nameserver domain
+// that is unregistered or lapses can be registered by anyone, who then
+// answers for the target.
+func CheckNameserverDomains(ctx context.Context, resolver Resolver, rdap *RDAPClient, domain string, nameservers []string, timeout time.Duration) []models.NameserverDomain {
+	own := RegistrableDomain(domain)
+
+	byDomain := make(map[string][]string)
+	for _, ns := range nameservers {
+		nsDomain := RegistrableDomain(ns)
+		if nsDomain == "" || nsDomain == own {
+			continue
+		}
+		byDomain[nsDomain] = append(byDomain[nsDomain], ns)
+	}
+
+	results := make([]models.NameserverDomain, 0, len(byDomain))
+	for nsDomain, hosts := range byDomain {
+		results = append(results, models.NameserverDomain{Domain: nsDomain, Nameservers: hosts})
+	}
+	sort.Slice(results, func(i, j int) bool { return results[i].Domain < results[j].Domain })
+
+	var wg sync.WaitGroup
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			checkNameserverDomain(ctx, resolver, rdap, &results[i], timeout)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
+
+func checkNameserverDomain(ctx context.Context, resolver Resolver, rdap *RDAPClient, result *models.NameserverDomain, timeout time.Duration) {
+	whois := CheckWHOIS(ctx, resolver, rdap, result.Domain, timeout)
+	switch {
+	case errors.Is(whois.Error, ErrDomainNotFound):
+		result.Status = models.StatusUnregistered
+		return
+	case whois.Error != nil:
+		result.Error = whois.Error.Error()
+		return
+	}
+
+	result.Registered = true
+	result.ExpiresAt = whois.ExpiresAt
+	result.ExpiresInDays = whois.ExpiresInDays
+	result.Status = models.CalculateExpirationStatus(whois.ExpiresAt)
+}
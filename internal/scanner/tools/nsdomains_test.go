@@ -0,0 +1,58 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"nsdigup/pkg/models"
+)
+
+func TestCheckNameserverDomains(t *testing.T) {
+	client := newTestRDAPClient(t, newTestRDAPServer(t))
+
+	nameservers := []string{
+		"ns1.example.test", "ns2.example.test", "ns.lapsed.test",
+		"ns.expired.test", "ns.expiring.test", "ns1.target.test",
+	}
+	results := CheckNameserverDomains(context.Background(), nil, client, "www.target.test", nameservers, 5*time.Second)
+
+	if len(results) != 4 {
+		t.Fatalf("Expected 4 nameserver domains without the target's own, got %+v", results)
+	}
+
+	registered := results[0]
+	if registered.Domain != "example.test" || !registered.Registered {
+		t.Errorf("Expected example.test to be registered, got %+v", registered)
+	}
+	if len(registered.Nameservers) != 2 {
+		t.Errorf("Expected both example.test nameservers, got %v", registered.Nameservers)
+	}
+	if registered.Status != models.StatusActive || registered.ExpiresAt.Year() != 2031 {
+		t.Errorf("Expected active registration until 2031, got %s %v", registered.Status, registered.ExpiresAt)
+	}
+
+	expired := results[1]
+	if expired.Domain != "expired.test" || !expired.Registered || expired.Status != models.StatusExpired {
+		t.Errorf("Expected expired.test to be expired, got %+v", expired)
+	}
+	if expired.ExpiresInDays >= 0 {
+		t.Errorf("Expected expired.test to have expired days ago, got %d days", expired.ExpiresInDays)
+	}
+
+	expiring := results[2]
+	if expiring.Domain != "expiring.test" || !expiring.Registered || expiring.Status != models.StatusExpiringSoon {
+		t.Errorf("Expected expiring.test to be expiring soon, got %+v", expiring)
+	}
+	if expiring.ExpiresInDays < 9 || expiring.ExpiresInDays > 10 {
+		t.Errorf("Expected expiring.test to expire in about 10 days, got %d", expiring.ExpiresInDays)
+	}
+
+	lapsed := results[3]
+	if lapsed.Domain != "lapsed.test" || lapsed.Registered || lapsed.Status != models.StatusUnregistered {
+		t.Errorf("Expected lapsed.test to be unregistered, got %+v", lapsed)
+	}
+	if lapsed.Error != "" {
+		t.Errorf("Expected no error for an unregistered domain, got %q", lapsed.Error)
+	}
+}
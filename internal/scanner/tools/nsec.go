@@ -0,0 +1,92 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// RFC 9276 section 3.2 lets validators treat NSEC3 zones with many
+// iterations as insecure, and answer SERVFAIL beyond that.
+const (
+	nsec3InsecureIterations = 100
+	nsec3ServfailIterations = 150
+)
+
+// checkDenialOfExistence asks for a name that does not exist in the signed
+// zone and inspects the NSEC or NSEC3 records proving it.
+func checkDenialOfExistence(ctx context.Context, resolver Resolver, zone string) (*models.DNSSECDenial, error) {
+	probe := dns.Fqdn(wildcardProbeLabel() + "." + strings.TrimSuffix(zone, "."))
+
+	msg := &dns.Msg{}
+	msg.SetQuestion(probe, dns.TypeA)
+	msg.SetEdns0(4096, true)
+	msg.CheckingDisabled = true
+
+	resp, err := resolver.Exchange(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("denial of existence query failed: %w", err)
+	}
+
+	denial := &models.DNSSECDenial{}
+	for _, rr := range resp.Ns {
+		switch rr := rr.(type) {
+		case *dns.NSEC:
+			denial.Method = models.DenialNSEC
+			// Compact denial ("black lies") answers with an NSEC owned by the
+			// queried name itself, which reveals nothing about other names
+			if !strings.EqualFold(rr.Hdr.Name, probe) {
+				denial.Walkable = true
+			}
+		case *dns.NSEC3:
+			denial.Method = models.DenialNSEC3
+			denial.Iterations = rr.Iterations
+			denial.Salt = strings.ToUpper(rr.Salt)
+			if rr.Flags&1 != 0 {
+				denial.OptOut = true
+			}
+		}
+	}
+
+	if denial.Method == "" {
+		return nil, nil
+	}
+
+	denial.Issues = lintDenial(denial)
+	return denial, nil
+}
+
+// lintDenial flags zone walking and NSEC3 parameters against RFC 9276.
+func lintDenial(denial *models.DNSSECDenial) []string {
+	var issues []string
+
+	if denial.Method == models.DenialNSEC {
+		if denial.Walkable {
+			issues = append(issues, "NSEC allows every name in the zone to be enumerated (zone walking)")
+		}
+		return issues
+	}
+
+	switch {
+	case denial.Iterations > nsec3ServfailIterations:
+		issues = append(issues, fmt.Sprintf("NSEC3 iterations %d: validators may answer SERVFAIL (RFC 9276 recommends 0)", denial.Iterations))
+	case denial.Iterations > nsec3InsecureIterations:
+		issues = append(issues, fmt.Sprintf("NSEC3 iterations %d: validators may treat the zone as insecure (RFC 9276 recommends 0)", denial.Iterations))
+	case denial.Iterations > 0:
+		issues = append(issues, fmt.Sprintf("NSEC3 iterations %d: RFC 9276 recommends 0", denial.Iterations))
+	}
+
+	if denial.Salt != "" {
+		issues = append(issues, "NSEC3 salt is set: RFC 9276 recommends an empty salt")
+	}
+
+	if denial.OptOut {
+		issues = append(issues, "NSEC3 opt-out is set: RFC 9276 recommends it only for large delegation-centric zones")
+	}
+
+	return issues
+}
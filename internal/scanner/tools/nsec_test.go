@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// denialZone answers every query with NXDOMAIN and the given authority
+// records, with "$QNAME" replaced by the queried name.
+func denialZone(t *testing.T, records ...string) dns.HandlerFunc {
+	t.Helper()
+
+	return func(w dns.ResponseWriter, r *dns.Msg) {
+		m := &dns.Msg{}
+		m.SetReply(r)
+		m.Rcode = dns.RcodeNameError
+
+		for _, record := range records {
+			rr, err := dns.NewRR(strings.ReplaceAll(record, "$QNAME", r.Question[0].Name))
+			if err != nil {
+				t.Errorf("Invalid test record %q: %v", record, err)
+				continue
+			}
+			m.Ns = append(m.Ns, rr)
+		}
+
+		w.WriteMsg(m)
+	}
+}
+
+func TestCheckDenialOfExistence(t *testing.T) {
+	tests := []struct {
+		name     string
+		records  []string
+		expected *models.DNSSECDenial
+		issues   int
+	}{
+		{
+			name:     "NSEC zone walking",
+			records:  []string{"example.test. 300 IN NSEC www.example.test. A NS SOA RRSIG NSEC DNSKEY"},
+			expected: &models.DNSSECDenial{Method: models.DenialNSEC, Walkable: true},
+			issues:   1,
+		},
+		{
+			name:     "compact NSEC",
+			records:  []string{`$QNAME 300 IN NSEC \000.$QNAME RRSIG NSEC`},
+			expected: &models.DNSSECDenial{Method: models.DenialNSEC},
+		},
+		{
+			name:     "NSEC3 per RFC 9276",
+			records:  []string{"2vptu5timamqttgl4luu9kg21e0aor3s.example.test. 300 IN NSEC3 1 0 0 - 2VPTU5TIMAMQTTGL4LUU9KG21E0AOR3T A"},
+			expected: &models.DNSSECDenial{Method: models.DenialNSEC3},
+		},
+		{
+			name:     "NSEC3 with legacy parameters",
+			records:  []string{"2vptu5timamqttgl4luu9kg21e0aor3s.example.test. 300 IN NSEC3 1 1 120 AABBCCDD 2VPTU5TIMAMQTTGL4LUU9KG21E0AOR3T A"},
+			expected: &models.DNSSECDenial{Method: models.DenialNSEC3, Iterations: 120, Salt: "AABBCCDD", OptOut: true},
+			issues:   3,
+		},
+		{
+			name: "unsigned answer",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			addr := startTestDNSServer(t, denialZone(t, tt.records...))
+			resolver := NewUpstreamResolver([]string{addr}, time.Second)
+
+			denial, err := checkDenialOfExistence(context.Background(), resolver, "example.test.")
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+
+			if tt.expected == nil {
+				if denial != nil {
+					t.Errorf("Expected no denial details, got %+v", denial)
+				}
+				return
+			}
+			if denial == nil {
+				t.Fatal("Expected denial details, got nil")
+			}
+
+			if denial.Method != tt.expected.Method || denial.Walkable != tt.expected.Walkable ||
+				denial.Iterations != tt.expected.Iterations || denial.Salt != tt.expected.Salt ||
+				denial.OptOut != tt.expected.OptOut {
+				t.Errorf("Expected %+v, got %+v", tt.expected, denial)
+			}
+
+			if len(denial.Issues) != tt.issues {
+				t.Errorf("Expected %d issues, got %v", tt.issues, denial.Issues)
+			}
+		})
+	}
+}
+
+func TestLintDenial_Iterations(t *testing.T) {
+	tests := []struct {
+		iterations uint16
+		expected   string
+	}{
+		{iterations: 10, expected: "RFC 9276 recommends 0"},
+		{iterations: 101, expected: "may treat the zone as insecure"},
+		{iterations: 151, expected: "may answer SERVFAIL"},
+	}
+
+	for _, tt := range tests {
+		issues := lintDenial(&models.DNSSECDenial{Method: models.DenialNSEC3, Iterations: tt.iterations})
+		if len(issues) != 1 || !strings.Contains(issues[0], tt.expected) {
+			t.Errorf("Iterations %d: expected %q, got %v", tt.iterations, tt.expected, issues)
+		}
+	}
+}
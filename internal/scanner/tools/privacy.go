@@ -0,0 +1,74 @@
+package tools
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"nsdigup/pkg/models"
+)
+
+// bundledPrivacyPatterns lists privacy and proxy services and the phrases
+// registries use when they redact registrant data, e.g. under the GDPR.
+//
+//go:embed data/privacy_patterns.json
+var bundledPrivacyPatterns []byte
+
+// privacyPatterns is the parsed bundled pattern list. Patterns are lowercase
+// substrings of the registrant name or organization.
+type privacyPatterns struct {
+	Services []struct {
+		Name     string   `json:"name"`
+		Patterns []string `json:"patterns"`
+	} `json:"services"`
+	Redactions []string `json:"redactions"`
+}
+
+var registrantPrivacy = func() privacyPatterns {
+	var patterns privacyPatterns
+	if err := json.Unmarshal(bundledPrivacyPatterns, &patterns); err != nil {
+		panic(fmt.Sprintf("invalid bundled privacy patterns: %v", err))
+	}
+	return patterns
+}()
+
+// detectPrivacy tells whether any of the registrant fields is hidden by a
+// privacy service or redacted, and names the service when it is known.
+func detectPrivacy(fields ...string) (redacted bool, service string) {
+	for _, field := range fields {
+		field = strings.ToLower(field)
+		if field == "" {
+			continue
+		}
+		for _, s := range registrantPrivacy.Services {
+			for _, pattern := range s.Patterns {
+				if strings.Contains(field, pattern) {
+					return true, s.Name
+				}
+			}
+		}
+		for _, pattern := range registrantPrivacy.Redactions {
+			if strings.Contains(field, pattern) {
+				redacted = true
+			}
+		}
+	}
+	return redacted, ""
+}
+
+// setOwner sets the owner to the registrant organization, or its name when
+// there is none. A registrant hidden behind a privacy service or redacted is
+// reported as models.OwnerRedacted, since the proxy isn't the owner.
+func (r *WHOISResult) setOwner(organization, name string) {
+	if redacted, service := detectPrivacy(organization, name); redacted {
+		r.Owner = models.OwnerRedacted
+		r.PrivacyService = service
+		return
+	}
+
+	r.Owner = organization
+	if r.Owner == "" {
+		r.Owner = name
+	}
+}
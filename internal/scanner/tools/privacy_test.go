@@ -0,0 +1,45 @@
+package tools
+
+import "testing"
+
+func TestDetectPrivacy(t *testing.T) {
+	tests := []struct {
+		name     string
+		fields   []string
+		redacted bool
+		service  string
+	}{
+		{"organization", []string{"Google LLC", "Domain Administrator"}, false, ""},
+		{"empty", []string{"", ""}, false, ""},
+		{"proxy organization", []string{"Withheld for Privacy ehf", ""}, true, "Withheld for Privacy"},
+		{"proxy in name", []string{"", "Contact Privacy Inc. Customer 7151571251"}, true, "Contact Privacy Inc."},
+		{"GDPR redaction", []string{"REDACTED FOR PRIVACY", "REDACTED FOR PRIVACY"}, true, ""},
+		{"statutory masking", []string{"", "Statutory Masking Enabled"}, true, ""},
+		{"redaction before proxy", []string{"Data Protected", "Super Privacy Service LTD c/o Dynadot"}, true, "Super Privacy Service"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			redacted, service := detectPrivacy(tt.fields...)
+			if redacted != tt.redacted {
+				t.Errorf("Expected redacted %v, got %v", tt.redacted, redacted)
+			}
+			if service != tt.service {
+				t.Errorf("Expected service %q, got %q", tt.service, service)
+			}
+		})
+	}
+}
+
+func TestWHOISResult_SetOwner(t *testing.T) {
+	result := WHOISResult{}
+	result.setOwner("", "Jane Doe")
+	if result.Owner != "Jane Doe" {
+		t.Errorf("Expected registrant name without organization, got %q", result.Owner)
+	}
+
+	result.setOwner("PrivacyGuardian.org llc", "Protected")
+	if result.Owner != "redacted" || result.PrivacyService != "PrivacyGuardian.org" {
+		t.Errorf("Expected redacted owner via PrivacyGuardian.org, got %q (%q)", result.Owner, result.PrivacyService)
+	}
+}
@@ -0,0 +1,50 @@
+package tools
+
+import (
+	"log/slog"
+	"net"
+
+	"github.com/weppos/publicsuffix-go/publicsuffix"
+
+	"nsdigup/internal/logger"
+)
+
+// publicSuffixes is the Public Suffix List used to find registrable domains.
+// It is the copy bundled with publicsuffix-go unless LoadPublicSuffixList
+// replaced it at startup.
+var publicSuffixes = publicsuffix.DefaultList
+
+// registrableOptions only consider the ICANN section of the list: the
+// private section (github.io, blogspot.com, ...) holds names registered by a
+// company, not at a registry.
+var registrableOptions = &publicsuffix.FindOptions{IgnorePrivate: true, DefaultRule: publicsuffix.DefaultRule}
+
+// LoadPublicSuffixList replaces the bundled Public Suffix List with the copy
+// at path, such as a fresh public_suffix_list.dat from publicsuffix.org.
+// When the file can't be loaded the bundled copy stays in use.
+func LoadPublicSuffixList(path string) {
+	list, err := publicsuffix.NewListFromFile(path, publicsuffix.DefaultParserOptions)
+	if err != nil {
+		logger.Get().Warn("failed to load public suffix list, using bundled copy",
+			slog.String("file", path),
+			slog.String("error", err.Error()))
+		return
+	}
+	publicSuffixes = list
+}
+
+// RegistrableDomain returns the registrable domain (eTLD+1) of a name, e.g.
+// "www.example.co.uk" -> "example.co.uk". It returns "" for a public suffix
+// itself, which can't be registered, and for an IP address.
+func RegistrableDomain(name string) string {
+	name = normalizeDomain(name)
+	if name == "" || net.ParseIP(name) != nil {
+		return ""
+	}
+
+	domain, err := publicsuffix.DomainFromListWithOptions(publicSuffixes, name, registrableOptions)
+	if err != nil {
+		return ""
+	}
+	return domain
+}
@@ -0,0 +1,77 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/weppos/publicsuffix-go/publicsuffix"
+)
+
+func TestRegistrableDomain(t *testing.T) {
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"example.com", "example.com"},
+		{"www.example.com", "example.com"},
+		{"WWW.Example.CO.UK.", "example.co.uk"},
+		{"a.b.example.com.au", "example.com.au"},
+		{"https://shop.example.co.jp:443", "example.co.jp"},
+		// Private suffixes are registered by a company, not at a registry
+		{"user.github.io", "github.io"},
+		{"co.uk", ""},
+		{"com", ""},
+		{"", ""},
+		// IP literals are scan targets too, but have no registrable domain
+		{"192.168.1.1", ""},
+		{"203.0.113.7:8080", ""},
+	}
+
+	for _, tt := range tests {
+		if got := RegistrableDomain(tt.name); got != tt.expected {
+			t.Errorf("Expected %q for %q, got %q", tt.expected, tt.name, got)
+		}
+	}
+}
+
+func TestGetParentDomain(t *testing.T) {
+	tests := []struct {
+		domain   string
+		expected string
+	}{
+		{"sub.example.com", "example.com"},
+		{"a.b.example.co.uk", "b.example.co.uk"},
+		{"example.co.uk", ""},
+		{"example.com", ""},
+		{"co.uk", ""},
+		{"192.168.1.1", ""},
+	}
+
+	for _, tt := range tests {
+		if got := getParentDomain(tt.domain); got != tt.expected {
+			t.Errorf("Expected %q for %q, got %q", tt.expected, tt.domain, got)
+		}
+	}
+}
+
+func TestLoadPublicSuffixList(t *testing.T) {
+	defer func(original *publicsuffix.List) { publicSuffixes = original }(publicSuffixes)
+
+	path := filepath.Join(t.TempDir(), "public_suffix_list.dat")
+	list := "// ===BEGIN ICANN DOMAINS===\ncom\nexample.com\n// ===END ICANN DOMAINS===\n"
+	if err := os.WriteFile(path, []byte(list), 0o644); err != nil {
+		t.Fatalf("Failed to write list: %v", err)
+	}
+
+	LoadPublicSuffixList(path)
+	if got := RegistrableDomain("www.shop.example.com"); got != "shop.example.com" {
+		t.Errorf("Expected the loaded list to be used, got %q", got)
+	}
+
+	// A missing file keeps the list in use
+	LoadPublicSuffixList(filepath.Join(t.TempDir(), "missing.dat"))
+	if got := RegistrableDomain("www.shop.example.com"); got != "shop.example.com" {
+		t.Errorf("Expected the list to be kept, got %q", got)
+	}
+}
@@ -0,0 +1,370 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"nsdigup/internal/logger"
+	"nsdigup/pkg/models"
+)
+
+// ianaRDAPBootstrapURL is where IANA publishes the RDAP bootstrap file for domains.
+const ianaRDAPBootstrapURL = "https://data.iana.org/rdap/dns.json"
+
+// bundledRDAPBootstrap is the copy of the IANA bootstrap file shipped with
+// the binary. It is kept unmodified and updated with `make rdap-bootstrap`.
+//
+//go:embed data/rdap_dns.json
+var bundledRDAPBootstrap []byte
+
+// ErrDomainNotFound is returned when the registry reports the domain as not registered.
+var ErrDomainNotFound = errors.New("domain not registered")
+
+// RDAPClient looks up domain registration data using RDAP (RFC 9082/9083).
+// The registry serving each TLD is found through the IANA bootstrap file
+// (RFC 9224), which is bundled and optionally refreshed from IANA.
+type RDAPClient struct {
+	mu        sync.RWMutex
+	services  map[string][]string
+	updatedAt time.Time
+
+	bootstrapURL string
+	refresh      time.Duration
+	refreshing   atomic.Bool
+	httpClient   *http.Client
+}
+
+// NewRDAPClient creates an RDAP client. The bootstrap is read from
+// bootstrapFile when set, otherwise the bundled copy is used. A positive
+// refresh interval re-downloads the bootstrap from IANA once it is older.
+// RDAP servers are resolved through resolver, or the system when it is nil.
+func NewRDAPClient(bootstrapFile string, refresh time.Duration, resolver Resolver) *RDAPClient {
+	client := &RDAPClient{
+		bootstrapURL: ianaRDAPBootstrapURL,
+		refresh:      refresh,
+		httpClient: &http.Client{
+			Timeout:   10 * time.Second,
+			Transport: newHTTPTransport(resolver, 10*time.Second),
+		},
+	}
+
+	if bootstrapFile != "" {
+		data, err := os.ReadFile(bootstrapFile)
+		if err == nil {
+			err = client.loadBootstrap(data, time.Now())
+		}
+		if err == nil {
+			return client
+		}
+		logger.Get().Warn("failed to load RDAP bootstrap file, using bundled copy",
+			slog.String("file", bootstrapFile),
+			slog.String("error", err.Error()))
+	}
+
+	// The bundled copy is never considered fresh, so it is replaced from
+	// IANA on first use when refreshing is enabled.
+	if err := client.loadBootstrap(bundledRDAPBootstrap, time.Time{}); err != nil {
+		panic(fmt.Sprintf("invalid bundled RDAP bootstrap: %v", err))
+	}
+
+	return client
+}
+
+// rdapBootstrap is the IANA bootstrap file format (RFC 9224, section 4)
+type rdapBootstrap struct {
+	Version  string       `json:"version"`
+	Services [][][]string `json:"services"`
+}
+
+func (c *RDAPClient) loadBootstrap(data []byte, updatedAt time.Time) error {
+	var bootstrap rdapBootstrap
+	if err := json.Unmarshal(data, &bootstrap); err != nil {
+		return fmt.Errorf("invalid RDAP bootstrap: %w", err)
+	}
+
+	services := make(map[string][]string)
+	for _, service := range bootstrap.Services {
+		if len(service) != 2 {
+			continue
+		}
+		for _, tld := range service[0] {
+			services[strings.ToLower(tld)] = service[1]
+		}
+	}
+
+	if len(services) == 0 {
+		return fmt.Errorf("RDAP bootstrap contains no services")
+	}
+
+	c.mu.Lock()
+	c.services = services
+	c.updatedAt = updatedAt
+	c.mu.Unlock()
+
+	return nil
+}
+
+// RefreshBootstrap downloads the bootstrap file and replaces the current one.
+func (c *RDAPClient) RefreshBootstrap(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", c.bootstrapURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("RDAP bootstrap download failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("RDAP bootstrap download failed: HTTP %d", resp.StatusCode)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, 4<<20))
+	if err != nil {
+		return err
+	}
+
+	return c.loadBootstrap(data, time.Now())
+}
+
+// maybeRefresh starts a background bootstrap refresh when the current one is stale.
+func (c *RDAPClient) maybeRefresh() {
+	if c.refresh <= 0 {
+		return
+	}
+
+	c.mu.RLock()
+	stale := time.Since(c.updatedAt) > c.refresh
+	c.mu.RUnlock()
+
+	if !stale || !c.refreshing.CompareAndSwap(false, true) {
+		return
+	}
+
+	go func() {
+		defer c.refreshing.Store(false)
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+		if err := c.RefreshBootstrap(ctx); err != nil {
+			logger.Get().Warn("RDAP bootstrap refresh failed",
+				slog.String("error", err.Error()))
+		}
+	}()
+}
+
+// serversFor returns the RDAP base URLs for the TLD of domain.
+func (c *RDAPClient) serversFor(domain string) []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	// Entries may be multi-label, so match the longest known suffix
+	labels := strings.Split(domain, ".")
+	for i := range labels {
+		if servers, ok := c.services[strings.Join(labels[i:], ".")]; ok {
+			return servers
+		}
+	}
+	return nil
+}
+
+// Lookup fetches the registration data of domain from its registry's RDAP service.
+func (c *RDAPClient) Lookup(ctx context.Context, domain string) (WHOISResult, error) {
+	c.maybeRefresh()
+
+	servers := c.serversFor(domain)
+	if len(servers) == 0 {
+		return WHOISResult{}, fmt.Errorf("no RDAP service known for %s", domain)
+	}
+
+	var lastErr error
+	for _, base := range servers {
+		result, err := c.query(ctx, base, domain)
+		if err == nil || errors.Is(err, ErrDomainNotFound) {
+			return result, err
+		}
+		lastErr = err
+	}
+
+	return WHOISResult{}, lastErr
+}
+
+func (c *RDAPClient) query(ctx context.Context, base, domain string) (WHOISResult, error) {
+	endpoint := strings.TrimSuffix(base, "/") + "/domain/" + url.PathEscape(domain)
+
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return WHOISResult{}, err
+	}
+	req.Header.Set("Accept", "application/rdap+json, application/json")
+	req.Header.Set("User-Agent", "nsdigup.sh/1.0 (Security Scanner)")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return WHOISResult{}, fmt.Errorf("RDAP request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return WHOISResult{}, ErrDomainNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return WHOISResult{}, fmt.Errorf("RDAP request failed: HTTP %d", resp.StatusCode)
+	}
+
+	var data rdapDomain
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 4<<20)).Decode(&data); err != nil {
+		return WHOISResult{}, fmt.Errorf("RDAP parse failed: %w", err)
+	}
+
+	return data.toResult(), nil
+}
+
+// rdapDomain is the subset of the RDAP domain object (RFC 9083, section 5.3) we use
+type rdapDomain struct {
+	LDHName  string         `json:"ldhName"`
+	Status   []string       `json:"status"`
+	Events   []rdapEvent    `json:"events"`
+	Entities []rdapEntity   `json:"entities"`
+	Redacted []rdapRedacted `json:"redacted"`
+}
+
+// rdapRedacted describes a field removed from the response (RFC 9537).
+type rdapRedacted struct {
+	Name struct {
+		Type        string `json:"type"`
+		Description string `json:"description"`
+	} `json:"name"`
+}
+
+type rdapEvent struct {
+	Action string `json:"eventAction"`
+	Date   string `json:"eventDate"`
+}
+
+type rdapEntity struct {
+	Roles      []string          `json:"roles"`
+	VCardArray []json.RawMessage `json:"vcardArray"`
+	Entities   []rdapEntity      `json:"entities"`
+}
+
+func (d *rdapDomain) toResult() WHOISResult {
+	result := WHOISResult{
+		Statuses: d.Status,
+		Source:   "rdap",
+	}
+
+	for _, event := range d.Events {
+		date, err := parseDate(event.Date)
+		if err != nil {
+			continue
+		}
+		switch event.Action {
+		case "registration":
+			result.CreatedAt = date
+		case "expiration":
+			result.ExpiresAt = date
+			result.ExpiresInDays = models.CalculateDaysUntilExpiration(date)
+		case "last changed":
+			result.UpdatedAt = date
+		}
+	}
+
+	if registrar := findEntity(d.Entities, "registrar"); registrar != nil {
+		result.Registrar = registrar.vcardValue("fn")
+
+		// The abuse contact is an entity of the registrar (RFC 9083 section 5.1)
+		if abuse := findEntity(registrar.Entities, "abuse"); abuse != nil {
+			result.AbuseEmail = abuse.vcardValue("email")
+			result.AbusePhone = strings.TrimPrefix(abuse.vcardValue("tel"), "tel:")
+		}
+	}
+
+	if registrant := findEntity(d.Entities, "registrant"); registrant != nil {
+		result.setOwner(registrant.vcardValue("org"), registrant.vcardValue("fn"))
+	}
+
+	// Registries that redact the registrant say so rather than leave it empty
+	if result.Owner == "" && d.registrantRedacted() {
+		result.Owner = models.OwnerRedacted
+	}
+
+	return result
+}
+
+// registrantRedacted tells whether the registrant name or organization was
+// redacted from the response.
+func (d *rdapDomain) registrantRedacted() bool {
+	for _, redacted := range d.Redacted {
+		name := strings.ToLower(redacted.Name.Type + " " + redacted.Name.Description)
+		if strings.Contains(name, "registrant") &&
+			(strings.Contains(name, "name") || strings.Contains(name, "organization")) {
+			return true
+		}
+	}
+	return false
+}
+
+// findEntity returns the first entity with the given role, searching nested entities too.
+func findEntity(entities []rdapEntity, role string) *rdapEntity {
+	for i := range entities {
+		for _, r := range entities[i].Roles {
+			if r == role {
+				return &entities[i]
+			}
+		}
+	}
+	for i := range entities {
+		if found := findEntity(entities[i].Entities, role); found != nil {
+			return found
+		}
+	}
+	return nil
+}
+
+// vcardValue returns the text value of the first jCard (RFC 7095) property with the given name.
+func (e *rdapEntity) vcardValue(name string) string {
+	if len(e.VCardArray) != 2 {
+		return ""
+	}
+
+	var properties [][]json.RawMessage
+	if err := json.Unmarshal(e.VCardArray[1], &properties); err != nil {
+		return ""
+	}
+
+	for _, property := range properties {
+		if len(property) < 4 {
+			continue
+		}
+		var propName string
+		if err := json.Unmarshal(property[0], &propName); err != nil || propName != name {
+			continue
+		}
+
+		// Values are usually strings, structured values (like org) are arrays
+		var value string
+		if err := json.Unmarshal(property[3], &value); err == nil {
+			return strings.TrimSpace(value)
+		}
+		var values []string
+		if err := json.Unmarshal(property[3], &values); err == nil && len(values) > 0 {
+			return strings.TrimSpace(values[0])
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,260 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+const testRDAPDomain = `{
+  "objectClassName": "domain",
+  "ldhName": "EXAMPLE.TEST",
+  "status": ["client transfer prohibited", "server delete prohibited"],
+  "events": [
+    {"eventAction": "registration", "eventDate": "2001-03-15T05:00:00Z"},
+    {"eventAction": "expiration", "eventDate": "2031-03-15T05:00:00Z"},
+    {"eventAction": "last changed", "eventDate": "2024-02-01T10:20:30.000Z"},
+    {"eventAction": "last update of RDAP database", "eventDate": "2025-01-01T00:00:00Z"}
+  ],
+  "entities": [
+    {
+      "objectClassName": "entity",
+      "roles": ["registrar"],
+      "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Example Registrar, Inc."]]],
+      "entities": [
+        {
+          "objectClassName": "entity",
+          "roles": ["abuse"],
+          "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["email", {}, "text", "abuse@registrar.test"], ["tel", {"type": "voice"}, "uri", "tel:+1.5555550100"]]]
+        }
+      ]
+    },
+    {
+      "objectClassName": "entity",
+      "roles": ["registrant"],
+      "vcardArray": ["vcard", [["version", {}, "text", "4.0"], ["fn", {}, "text", "Jane Doe"], ["org", {}, "text", "Example Corp"]]]
+    }
+  ]
+}`
+
+// newTestRDAPServer serves example.test, expired.test that expired ten days
+// ago and expiring.test that expires in ten days, and reports every other
+// domain as not found.
+func newTestRDAPServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/rdap/domain/example.test":
+			w.Header().Set("Content-Type", "application/rdap+json")
+			fmt.Fprint(w, testRDAPDomain)
+		case "/rdap/domain/expired.test", "/rdap/domain/expiring.test":
+			expires := time.Now().AddDate(0, 0, 10)
+			if r.URL.Path == "/rdap/domain/expired.test" {
+				expires = time.Now().AddDate(0, 0, -10)
+			}
+			w.Header().Set("Content-Type", "application/rdap+json")
+			fmt.Fprintf(w, `{"objectClassName": "domain", "ldhName": "%s", "events": [{"eventAction": "expiration", "eventDate": "%s"}]}`,
+				r.URL.Path[len("/rdap/domain/"):], expires.UTC().Format(time.RFC3339))
+		case "/dns.json":
+			fmt.Fprintf(w, `{"version": "1.0", "services": [[["test", "example"], ["%s/rdap/"]]]}`, "http://"+r.Host)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestRDAPClient returns a client whose bootstrap points .test at server.
+func newTestRDAPClient(t *testing.T, server *httptest.Server) *RDAPClient {
+	t.Helper()
+	client := NewRDAPClient("", 0, nil)
+	client.bootstrapURL = server.URL + "/dns.json"
+	if err := client.RefreshBootstrap(context.Background()); err != nil {
+		t.Fatalf("Failed to load test bootstrap: %v", err)
+	}
+	return client
+}
+
+func TestRDAPClient_Lookup(t *testing.T) {
+	client := newTestRDAPClient(t, newTestRDAPServer(t))
+
+	result, err := client.Lookup(context.Background(), "example.test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	if result.Registrar != "Example Registrar, Inc." {
+		t.Errorf("Unexpected registrar: %q", result.Registrar)
+	}
+
+	if result.Owner != "Example Corp" {
+		t.Errorf("Expected registrant organization, got %q", result.Owner)
+	}
+
+	if result.AbuseEmail != "abuse@registrar.test" || result.AbusePhone != "+1.5555550100" {
+		t.Errorf("Unexpected abuse contact: %q %q", result.AbuseEmail, result.AbusePhone)
+	}
+
+	if !result.CreatedAt.Equal(time.Date(2001, 3, 15, 5, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected creation date: %v", result.CreatedAt)
+	}
+
+	if !result.ExpiresAt.Equal(time.Date(2031, 3, 15, 5, 0, 0, 0, time.UTC)) {
+		t.Errorf("Unexpected expiration date: %v", result.ExpiresAt)
+	}
+
+	if result.UpdatedAt.Year() != 2024 {
+		t.Errorf("Expected 'last changed' as updated date, got %v", result.UpdatedAt)
+	}
+
+	if len(result.Statuses) != 2 || result.Statuses[0] != "client transfer prohibited" {
+		t.Errorf("Unexpected statuses: %v", result.Statuses)
+	}
+
+	if result.Source != "rdap" {
+		t.Errorf("Expected source 'rdap', got %q", result.Source)
+	}
+}
+
+func TestRDAPDomain_RedactedRegistrant(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		owner   string
+		service string
+	}{
+		{
+			name: "privacy service",
+			body: `{"entities": [{"roles": ["registrant"], "vcardArray": ["vcard", [["version", {}, "text", "4.0"],
+				["fn", {}, "text", "Registration Private"], ["org", {}, "text", "Domains By Proxy, LLC"]]]}]}`,
+			owner:   "redacted",
+			service: "Domains By Proxy",
+		},
+		{
+			name: "redacted for privacy",
+			body: `{"entities": [{"roles": ["registrant"], "vcardArray": ["vcard", [["version", {}, "text", "4.0"],
+				["fn", {}, "text", "REDACTED FOR PRIVACY"]]]}]}`,
+			owner: "redacted",
+		},
+		{
+			name:  "RFC 9537 redaction",
+			body:  `{"redacted": [{"name": {"type": "Registrant Name"}, "method": "removal"}]}`,
+			owner: "redacted",
+		},
+		{
+			name:  "no registrant",
+			body:  `{}`,
+			owner: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var domain rdapDomain
+			if err := json.Unmarshal([]byte(tt.body), &domain); err != nil {
+				t.Fatalf("Invalid test body: %v", err)
+			}
+
+			result := domain.toResult()
+			if result.Owner != tt.owner {
+				t.Errorf("Expected owner %q, got %q", tt.owner, result.Owner)
+			}
+			if result.PrivacyService != tt.service {
+				t.Errorf("Expected privacy service %q, got %q", tt.service, result.PrivacyService)
+			}
+		})
+	}
+}
+
+func TestRDAPClient_NotFound(t *testing.T) {
+	client := newTestRDAPClient(t, newTestRDAPServer(t))
+
+	_, err := client.Lookup(context.Background(), "unregistered.test")
+	if !errors.Is(err, ErrDomainNotFound) {
+		t.Errorf("Expected ErrDomainNotFound, got: %v", err)
+	}
+}
+
+func TestRDAPClient_UnknownTLD(t *testing.T) {
+	client := newTestRDAPClient(t, newTestRDAPServer(t))
+
+	if _, err := client.Lookup(context.Background(), "example.invalid"); err == nil {
+		t.Error("Expected error for TLD without RDAP service")
+	}
+}
+
+func TestRDAPClient_BootstrapFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "dns.json")
+	bootstrap := `{"version": "1.0", "services": [[["co.test"], ["https://rdap.example/"]]]}`
+	if err := os.WriteFile(path, []byte(bootstrap), 0o600); err != nil {
+		t.Fatalf("Failed to write bootstrap: %v", err)
+	}
+
+	client := NewRDAPClient(path, 0, nil)
+
+	servers := client.serversFor("shop.co.test")
+	if len(servers) != 1 || servers[0] != "https://rdap.example/" {
+		t.Errorf("Expected multi-label TLD match, got %v", servers)
+	}
+
+	fixture := NewRDAPClient(filepath.Join("testdata", "rdap_dns.json"), 0, nil)
+	if servers := fixture.serversFor("example.fr"); len(servers) != 1 || servers[0] != "https://rdap.nic.fr/" {
+		t.Errorf("Expected .fr from the testdata bootstrap, got %v", servers)
+	}
+
+	// Invalid files fall back to the bundled bootstrap
+	fallback := NewRDAPClient(filepath.Join(t.TempDir(), "missing.json"), 0, nil)
+	if len(fallback.serversFor("example.com")) == 0 {
+		t.Error("Expected bundled bootstrap to serve .com")
+	}
+}
+
+func TestCheckWHOIS_UsesRDAP(t *testing.T) {
+	client := newTestRDAPClient(t, newTestRDAPServer(t))
+
+	result := CheckWHOIS(context.Background(), nil, client, "EXAMPLE.test.", 5*time.Second)
+	if result.Error != nil {
+		t.Fatalf("Unexpected error: %v", result.Error)
+	}
+
+	if result.Source != "rdap" || result.Registrar == "" {
+		t.Errorf("Expected RDAP result, got %+v", result)
+	}
+
+	notFound := CheckWHOIS(context.Background(), nil, client, "unregistered.test", 5*time.Second)
+	if !errors.Is(notFound.Error, ErrDomainNotFound) {
+		t.Errorf("Expected RDAP not found without WHOIS fallback, got: %v", notFound.Error)
+	}
+}
+
+func TestParseDate(t *testing.T) {
+	expected := time.Date(1997, 9, 15, 4, 0, 0, 0, time.UTC)
+
+	for _, value := range []string{
+		"1997-09-15T04:00:00Z",
+		"1997-09-15T04:00:00+0000",
+		"1997-09-15T06:00:00+02:00",
+		"1997-09-15 04:00:00",
+	} {
+		date, err := parseDate(value)
+		if err != nil {
+			t.Errorf("Expected %q to parse, got %v", value, err)
+			continue
+		}
+		if !date.Equal(expected) {
+			t.Errorf("Expected %v for %q, got %v", expected, value, date)
+		}
+	}
+
+	if _, err := parseDate("sometime in 1997"); err == nil {
+		t.Error("Expected unparseable date to fail")
+	}
+}
@@ -0,0 +1,111 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// inventoryTypes are the record types listed for the domain itself, in
+// report order.
+var inventoryTypes = []uint16{
+	dns.TypeSOA, dns.TypeA, dns.TypeAAAA, dns.TypeCNAME, dns.TypeMX,
+	dns.TypeTXT, dns.TypeHTTPS, dns.TypeSVCB,
+}
+
+// wellKnownServices are the SRV names looked up under the domain.
+var wellKnownServices = []string{
+	"_autodiscover._tcp",
+	"_caldav._tcp", "_caldavs._tcp",
+	"_carddav._tcp", "_carddavs._tcp",
+	"_imap._tcp", "_imaps._tcp",
+	"_pop3._tcp", "_pop3s._tcp",
+	"_submission._tcp", "_submissions._tcp",
+	"_sip._tcp", "_sip._udp", "_sips._tcp",
+	"_xmpp-client._tcp", "_xmpp-server._tcp",
+	"_matrix._tcp",
+	"_ldap._tcp", "_kerberos._udp",
+}
+
+type inventoryQuery struct {
+	name  string
+	qtype uint16
+}
+
+// GetRecords lists the common record types of the domain with their TTLs:
+// SOA, A, AAAA, CNAME, MX, TXT, HTTPS and SVCB at the domain, the CNAME of
+// its www name, and SRV records of well-known services. All lookups run
+// concurrently, records are returned in query order.
+func GetRecords(ctx context.Context, resolver Resolver, domain string) ([]models.DNSRecord, error) {
+	domain = normalizeDomain(domain)
+
+	var queries []inventoryQuery
+	for _, qtype := range inventoryTypes {
+		queries = append(queries, inventoryQuery{name: domain, qtype: qtype})
+	}
+	queries = append(queries, inventoryQuery{name: "www." + domain, qtype: dns.TypeCNAME})
+	for _, service := range wellKnownServices {
+		queries = append(queries, inventoryQuery{name: service + "." + domain, qtype: dns.TypeSRV})
+	}
+
+	results := make([][]models.DNSRecord, len(queries))
+	errs := make([]error, len(queries))
+
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], errs[i] = queryInventory(ctx, resolver, q.name, q.qtype)
+		}()
+	}
+	wg.Wait()
+
+	var records []models.DNSRecord
+	for _, result := range results {
+		records = append(records, result...)
+	}
+
+	// Only fail when the domain itself could not be queried at all
+	if len(records) == 0 {
+		for _, err := range errs {
+			if err != nil {
+				return nil, fmt.Errorf("DNS record lookup failed: %w", err)
+			}
+		}
+	}
+
+	return records, nil
+}
+
+// queryInventory returns the records of type qtype in the answer for name.
+// Names that don't exist simply have no records.
+func queryInventory(ctx context.Context, resolver Resolver, name string, qtype uint16) ([]models.DNSRecord, error) {
+	resp, err := query(ctx, resolver, name, qtype)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Rcode != dns.RcodeSuccess {
+		return nil, nil
+	}
+
+	var records []models.DNSRecord
+	for _, rr := range resp.Answer {
+		header := rr.Header()
+		if header.Rrtype != qtype {
+			continue
+		}
+		records = append(records, models.DNSRecord{
+			Name:  normalizeHost(header.Name),
+			Type:  dns.TypeToString[qtype],
+			TTL:   header.Ttl,
+			Value: strings.TrimSpace(strings.TrimPrefix(rr.String(), header.String())),
+		})
+	}
+	return records, nil
+}
@@ -0,0 +1,60 @@
+package tools
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestGetRecords(t *testing.T) {
+	addr := startTestDNSServer(t, testZone(t,
+		"example.test. 3600 IN SOA ns1.example.test. hostmaster.example.test. 2024010101 7200 3600 1209600 300",
+		"example.test. 300 IN A 192.0.2.10",
+		"example.test. 300 IN AAAA 2001:db8::10",
+		"example.test. 3600 IN MX 10 mail.example.test.",
+		`example.test. 3600 IN TXT "v=spf1 -all"`,
+		`example.test. 300 IN HTTPS 1 . alpn="h2,h3"`,
+		"www.example.test. 300 IN CNAME example.test.",
+		"_submissions._tcp.example.test. 3600 IN SRV 0 1 465 mail.example.test.",
+	))
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+	records, err := GetRecords(context.Background(), resolver, "example.test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	expected := []struct {
+		name, rtype, value string
+		ttl                uint32
+	}{
+		{"example.test", "SOA", "ns1.example.test. hostmaster.example.test. 2024010101 7200 3600 1209600 300", 3600},
+		{"example.test", "A", "192.0.2.10", 300},
+		{"example.test", "AAAA", "2001:db8::10", 300},
+		{"example.test", "MX", "10 mail.example.test.", 3600},
+		{"example.test", "TXT", `"v=spf1 -all"`, 3600},
+		{"example.test", "HTTPS", `1 . alpn="h2,h3"`, 300},
+		{"www.example.test", "CNAME", "example.test.", 300},
+		{"_submissions._tcp.example.test", "SRV", "0 1 465 mail.example.test.", 3600},
+	}
+
+	if len(records) != len(expected) {
+		t.Fatalf("Expected %d records, got %d: %+v", len(expected), len(records), records)
+	}
+
+	for i, want := range expected {
+		got := records[i]
+		if got.Name != want.name || got.Type != want.rtype || got.Value != want.value || got.TTL != want.ttl {
+			t.Errorf("Record %d: expected %s %d %s %s, got %s %d %s %s", i,
+				want.name, want.ttl, want.rtype, want.value, got.Name, got.TTL, got.Type, got.Value)
+		}
+	}
+}
+
+func TestGetRecords_ResolverFailure(t *testing.T) {
+	// Nothing listens on the discard port
+	resolver := NewUpstreamResolver([]string{"127.0.0.1:9"}, 100*time.Millisecond)
+	if _, err := GetRecords(context.Background(), resolver, "example.test"); err == nil {
+		t.Error("Expected error when no query succeeds")
+	}
+}
@@ -0,0 +1,211 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/internal/config"
+)
+
+// Resolver sends DNS queries to a recursive resolver. Every DNS lookup made
+// by the scanner tools goes through a Resolver, so the transport (system,
+// plain upstream servers, DNS-over-TLS or DNS-over-HTTPS) is chosen once in
+// configuration.
+type Resolver interface {
+	Exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, error)
+}
+
+// resolvConfPath is where the system resolver configuration is read from.
+const resolvConfPath = "/etc/resolv.conf"
+
+// NewResolver builds the Resolver described by the DNS configuration.
+// An empty mode falls back to the system resolver.
+func NewResolver(cfg config.DNSConfig) Resolver {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = 3 * time.Second
+	}
+
+	switch cfg.Mode {
+	case config.ResolverModeUpstream:
+		return NewUpstreamResolver(cfg.Servers, timeout)
+	case config.ResolverModeDoT:
+		return NewDoTResolver(cfg.Servers, timeout)
+	case config.ResolverModeDoH:
+		return NewDoHResolver(cfg.Servers, timeout)
+	default:
+		return NewSystemResolver(timeout)
+	}
+}
+
+// NewSystemResolver uses the nameservers listed in /etc/resolv.conf,
+// falling back to a local resolver when the file cannot be read.
+func NewSystemResolver(timeout time.Duration) Resolver {
+	servers := []string{"127.0.0.1:53"}
+	if conf, err := dns.ClientConfigFromFile(resolvConfPath); err == nil && len(conf.Servers) > 0 {
+		servers = servers[:0]
+		for _, server := range conf.Servers {
+			servers = append(servers, net.JoinHostPort(server, conf.Port))
+		}
+	}
+	return NewUpstreamResolver(servers, timeout)
+}
+
+// NewUpstreamResolver queries the given servers over UDP (falling back to TCP
+// on truncation), trying each in order until one answers.
+func NewUpstreamResolver(servers []string, timeout time.Duration) Resolver {
+	return &upstreamResolver{
+		servers: withDefaultPort(servers, "53"),
+		client:  &dns.Client{Timeout: timeout},
+	}
+}
+
+// NewDoTResolver queries the given servers using DNS-over-TLS (RFC 7858).
+func NewDoTResolver(servers []string, timeout time.Duration) Resolver {
+	return &upstreamResolver{
+		servers: withDefaultPort(servers, "853"),
+		client: &dns.Client{
+			Net:       "tcp-tls",
+			Timeout:   timeout,
+			TLSConfig: &tls.Config{MinVersion: tls.VersionTLS12},
+		},
+	}
+}
+
+// NewDoHResolver queries the given https endpoints using DNS-over-HTTPS (RFC 8484).
+func NewDoHResolver(endpoints []string, timeout time.Duration) Resolver {
+	return &dohResolver{
+		endpoints: endpoints,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+type upstreamResolver struct {
+	servers []string
+	client  *dns.Client
+}
+
+func (r *upstreamResolver) Exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	var lastErr error
+	for _, server := range r.servers {
+		var resp *dns.Msg
+		var err error
+		if r.client.Net == "" || r.client.Net == "udp" {
+			resp, err = exchangeWithFallback(ctx, r.client, msg, server)
+		} else {
+			resp, _, err = r.client.ExchangeContext(ctx, msg, server)
+		}
+		if err == nil {
+			return resp, nil
+		}
+
+		lastErr = fmt.Errorf("%s: %w", server, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no DNS servers configured")
+	}
+	return nil, lastErr
+}
+
+type dohResolver struct {
+	endpoints []string
+	client    *http.Client
+}
+
+func (r *dohResolver) Exchange(ctx context.Context, msg *dns.Msg) (*dns.Msg, error) {
+	packed, err := msg.Pack()
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack DNS message: %w", err)
+	}
+
+	var lastErr error
+	for _, endpoint := range r.endpoints {
+		resp, err := r.post(ctx, endpoint, packed)
+		if err == nil {
+			return resp, nil
+		}
+
+		lastErr = fmt.Errorf("%s: %w", endpoint, err)
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no DoH endpoints configured")
+	}
+	return nil, lastErr
+}
+
+func (r *dohResolver) post(ctx context.Context, endpoint string, packed []byte) (*dns.Msg, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", endpoint, bytes.NewReader(packed))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/dns-message")
+	req.Header.Set("Accept", "application/dns-message")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected HTTP status %d", resp.StatusCode)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, dns.MaxMsgSize))
+	if err != nil {
+		return nil, err
+	}
+
+	reply := &dns.Msg{}
+	if err := reply.Unpack(body); err != nil {
+		return nil, fmt.Errorf("invalid DNS response: %w", err)
+	}
+	return reply, nil
+}
+
+// exchangeWithFallback sends msg over UDP and retries over TCP when the
+// response is truncated.
+func exchangeWithFallback(ctx context.Context, client *dns.Client, msg *dns.Msg, server string) (*dns.Msg, error) {
+	resp, _, err := client.ExchangeContext(ctx, msg, server)
+	if err != nil {
+		return nil, err
+	}
+
+	if resp.Truncated {
+		tcpClient := &dns.Client{Net: "tcp", Timeout: client.Timeout}
+		resp, _, err = tcpClient.ExchangeContext(ctx, msg, server)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return resp, nil
+}
+
+// withDefaultPort appends port to every server given without one.
+func withDefaultPort(servers []string, port string) []string {
+	result := make([]string, 0, len(servers))
+	for _, server := range servers {
+		if _, _, err := net.SplitHostPort(server); err != nil {
+			server = net.JoinHostPort(server, port)
+		}
+		result = append(result, server)
+	}
+	return result
+}
@@ -0,0 +1,255 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/internal/config"
+)
+
+// startTestDNSServer serves handler over UDP and TCP on the same random local
+// port and returns its address.
+func startTestDNSServer(t *testing.T, handler dns.Handler) string {
+	t.Helper()
+
+	// The UDP port may already be taken for TCP, so try a few ports
+	var (
+		pc   net.PacketConn
+		ln   net.Listener
+		addr string
+		err  error
+	)
+	for range 5 {
+		pc, err = net.ListenPacket("udp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatalf("Failed to listen on UDP: %v", err)
+		}
+		addr = pc.LocalAddr().String()
+
+		ln, err = net.Listen("tcp", addr)
+		if err == nil {
+			break
+		}
+		pc.Close()
+	}
+	if err != nil {
+		t.Fatalf("Failed to listen on TCP: %v", err)
+	}
+
+	for _, server := range []*dns.Server{
+		{PacketConn: pc, Handler: handler},
+		{Listener: ln, Handler: handler},
+	} {
+		started := make(chan struct{})
+		server.NotifyStartedFunc = func() { close(started) }
+		go server.ActivateAndServe()
+		<-started
+		t.Cleanup(func() { server.Shutdown() })
+	}
+
+	return addr
+}
+
+// testZone answers queries from a fixed set of records. Names without any
+// records get NXDOMAIN, names with records of other types get NODATA.
+func testZone(t *testing.T, records ...string) dns.HandlerFunc {
+	t.Helper()
+
+	var rrs []dns.RR
+	for _, record := range records {
+		rr, err := dns.NewRR(record)
+		if err != nil {
+			t.Fatalf("Invalid test record %q: %v", record, err)
+		}
+		rrs = append(rrs, rr)
+	}
+
+	return func(w dns.ResponseWriter, r *dns.Msg) {
+		m := &dns.Msg{}
+		m.SetReply(r)
+		m.Authoritative = true
+
+		q := r.Question[0]
+		nameExists := false
+		for _, rr := range rrs {
+			if !strings.EqualFold(rr.Header().Name, q.Name) {
+				continue
+			}
+			nameExists = true
+			if rr.Header().Rrtype == q.Qtype || q.Qtype == dns.TypeANY {
+				m.Answer = append(m.Answer, dns.Copy(rr))
+			}
+		}
+		if !nameExists {
+			m.Rcode = dns.RcodeNameError
+		}
+
+		w.WriteMsg(m)
+	}
+}
+
+func TestUpstreamResolver_Lookups(t *testing.T) {
+	addr := startTestDNSServer(t, testZone(t,
+		"example.test. 300 IN AAAA 2001:db8::10",
+		"example.test. 300 IN A 192.0.2.10",
+		"example.test. 300 IN NS ns1.example.test.",
+		"example.test. 300 IN NS ns2.example.test.",
+		`example.test. 300 IN TXT "v=spf1 " "-all"`,
+		`_dmarc.example.test. 300 IN TXT "v=DMARC1; p=reject"`,
+	))
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+	ctx := context.Background()
+
+	addresses, err := GetAddresses(ctx, resolver, "example.test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(addresses) != 2 || addresses[0].IP != "192.0.2.10" {
+		t.Errorf("Expected IPv4 address first, got %+v", addresses)
+	}
+
+	nameservers, err := GetNameservers(ctx, resolver, "example.test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(nameservers) != 2 || nameservers[0] != "ns1.example.test" {
+		t.Errorf("Unexpected nameservers: %v", nameservers)
+	}
+
+	emailSec, err := CheckEmailSecurity(ctx, resolver, "example.test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if emailSec.SPF != "v=spf1 -all" {
+		t.Errorf("Expected TXT strings to be joined, got %q", emailSec.SPF)
+	}
+	if emailSec.DMARC != "reject" {
+		t.Errorf("Expected DMARC policy 'reject', got %q", emailSec.DMARC)
+	}
+
+	if _, err := GetAddresses(ctx, resolver, "missing.example.test"); err == nil {
+		t.Error("Expected error for NXDOMAIN")
+	}
+}
+
+func TestUpstreamResolver_Failover(t *testing.T) {
+	addr := startTestDNSServer(t, testZone(t, "example.test. 300 IN A 192.0.2.10"))
+
+	// Nothing listens on the first server
+	dead, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to reserve port: %v", err)
+	}
+	deadAddr := dead.LocalAddr().String()
+	dead.Close()
+
+	resolver := NewUpstreamResolver([]string{deadAddr, addr}, 500*time.Millisecond)
+
+	addresses, err := GetAddresses(context.Background(), resolver, "example.test")
+	if err != nil {
+		t.Fatalf("Expected fallback to second server, got error: %v", err)
+	}
+	if len(addresses) != 1 || addresses[0].IP != "192.0.2.10" {
+		t.Errorf("Expected 192.0.2.10, got %+v", addresses)
+	}
+}
+
+func TestUpstreamResolver_TruncatedFallsBackToTCP(t *testing.T) {
+	zone := testZone(t, "example.test. 300 IN A 192.0.2.10")
+	addr := startTestDNSServer(t, dns.HandlerFunc(func(w dns.ResponseWriter, r *dns.Msg) {
+		if w.RemoteAddr().Network() == "udp" {
+			m := &dns.Msg{}
+			m.SetReply(r)
+			m.Truncated = true
+			w.WriteMsg(m)
+			return
+		}
+		zone(w, r)
+	}))
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+
+	addresses, err := GetAddresses(context.Background(), resolver, "example.test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(addresses) != 1 || addresses[0].IP != "192.0.2.10" {
+		t.Errorf("Expected answer from TCP retry, got %+v", addresses)
+	}
+}
+
+func TestDoHResolver(t *testing.T) {
+	addr := startTestDNSServer(t, testZone(t, "example.test. 300 IN A 192.0.2.20"))
+
+	doh := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "POST" || r.Header.Get("Content-Type") != "application/dns-message" {
+			http.Error(w, "bad request", http.StatusBadRequest)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		msg := &dns.Msg{}
+		if err := msg.Unpack(body); err != nil {
+			http.Error(w, "bad message", http.StatusBadRequest)
+			return
+		}
+
+		resp, err := dns.Exchange(msg, addr)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadGateway)
+			return
+		}
+
+		packed, _ := resp.Pack()
+		w.Header().Set("Content-Type", "application/dns-message")
+		w.Write(packed)
+	}))
+	defer doh.Close()
+
+	resolver := NewDoHResolver([]string{doh.URL + "/dns-query"}, time.Second)
+
+	addresses, err := GetAddresses(context.Background(), resolver, "example.test")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(addresses) != 1 || addresses[0].IP != "192.0.2.20" {
+		t.Errorf("Expected 192.0.2.20, got %+v", addresses)
+	}
+}
+
+func TestNewResolver_Modes(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  config.DNSConfig
+		want string
+	}{
+		{name: "empty mode uses system", cfg: config.DNSConfig{}, want: "*tools.upstreamResolver"},
+		{name: "upstream", cfg: config.DNSConfig{Mode: config.ResolverModeUpstream, Servers: []string{"10.0.0.53"}}, want: "*tools.upstreamResolver"},
+		{name: "dot", cfg: config.DNSConfig{Mode: config.ResolverModeDoT, Servers: []string{"1.1.1.1"}}, want: "*tools.upstreamResolver"},
+		{name: "doh", cfg: config.DNSConfig{Mode: config.ResolverModeDoH, Servers: []string{"https://dns.example/dns-query"}}, want: "*tools.dohResolver"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resolver := NewResolver(tt.cfg)
+			if got := fmt.Sprintf("%T", resolver); got != tt.want {
+				t.Errorf("Expected %s, got %s", tt.want, got)
+			}
+		})
+	}
+
+	dot := NewResolver(config.DNSConfig{Mode: config.ResolverModeDoT, Servers: []string{"1.1.1.1"}}).(*upstreamResolver)
+	if dot.servers[0] != "1.1.1.1:853" || dot.client.Net != "tcp-tls" {
+		t.Errorf("Expected DoT on port 853, got %s over %s", dot.servers[0], dot.client.Net)
+	}
+}
@@ -0,0 +1,185 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// Recommended SOA timer ranges in seconds, from RFC 1912 section 2.2 and,
+// for the negative caching TTL in the minimum field, RFC 2308 section 5.
+const (
+	soaRefreshMin = 1200    // 20 minutes
+	soaRefreshMax = 43200   // 12 hours
+	soaRetryMin   = 120     // 2 minutes
+	soaRetryMax   = 7200    // 2 hours
+	soaExpireMin  = 1209600 // 2 weeks
+	soaExpireMax  = 2419200 // 4 weeks
+	soaMinimumMin = 300     // 5 minutes
+	soaMinimumMax = 86400   // 1 day
+)
+
+// CheckSOA fetches the SOA record of the zone holding the domain and lints
+// its timers, serial, MNAME and RNAME against RFC 1912 recommendations.
+func CheckSOA(ctx context.Context, resolver Resolver, domain string) models.SOAFindings {
+	findings := models.SOAFindings{}
+
+	soa, err := lookupSOA(ctx, resolver, normalizeDomain(domain))
+	if err != nil {
+		findings.Error = err.Error()
+		return findings
+	}
+
+	findings.Zone = normalizeHost(soa.Hdr.Name)
+	findings.MName = normalizeHost(soa.Ns)
+	findings.RName = rnameToMailbox(soa.Mbox)
+	findings.Serial = soa.Serial
+	findings.Refresh = soa.Refresh
+	findings.Retry = soa.Retry
+	findings.Expire = soa.Expire
+	findings.Minimum = soa.Minttl
+
+	findings.Issues = lintSOATimers(soa)
+	if issue := lintSOASerial(soa.Serial, time.Now()); issue != "" {
+		findings.Issues = append(findings.Issues, issue)
+	}
+	if issue := lintSOARName(soa.Mbox); issue != "" {
+		findings.Issues = append(findings.Issues, issue)
+	}
+
+	nameservers, err := GetNameservers(ctx, resolver, findings.Zone)
+	if err == nil && len(nameservers) > 0 {
+		listed := false
+		for _, ns := range nameservers {
+			if strings.EqualFold(normalizeHost(ns), findings.MName) {
+				listed = true
+				break
+			}
+		}
+		if !listed {
+			findings.Issues = append(findings.Issues,
+				fmt.Sprintf("MNAME %s is not listed in the NS set", findings.MName))
+		}
+	}
+
+	return findings
+}
+
+// lookupSOA returns the SOA of the zone holding name. Names below the zone
+// apex have no SOA themselves, the zone's SOA is then in the authority section.
+func lookupSOA(ctx context.Context, resolver Resolver, name string) (*dns.SOA, error) {
+	resp, err := query(ctx, resolver, name, dns.TypeSOA)
+	if err != nil {
+		return nil, fmt.Errorf("SOA lookup failed: %w", err)
+	}
+
+	for _, section := range [][]dns.RR{resp.Answer, resp.Ns} {
+		for _, rr := range section {
+			if soa, ok := rr.(*dns.SOA); ok {
+				return soa, nil
+			}
+		}
+	}
+
+	return nil, fmt.Errorf("no SOA record found for %s", name)
+}
+
+func lintSOATimers(soa *dns.SOA) []string {
+	var issues []string
+
+	if soa.Refresh < soaRefreshMin || soa.Refresh > soaRefreshMax {
+		issues = append(issues, fmt.Sprintf("Refresh %d is outside the recommended %d-%d", soa.Refresh, soaRefreshMin, soaRefreshMax))
+	}
+
+	if soa.Retry >= soa.Refresh {
+		issues = append(issues, fmt.Sprintf("Retry %d is not shorter than refresh %d", soa.Retry, soa.Refresh))
+	} else if soa.Retry < soaRetryMin || soa.Retry > soaRetryMax {
+		issues = append(issues, fmt.Sprintf("Retry %d is outside the recommended %d-%d", soa.Retry, soaRetryMin, soaRetryMax))
+	}
+
+	if soa.Expire <= soa.Refresh {
+		issues = append(issues, fmt.Sprintf("Expire %d is shorter than refresh %d", soa.Expire, soa.Refresh))
+	} else if soa.Expire < soaExpireMin || soa.Expire > soaExpireMax {
+		issues = append(issues, fmt.Sprintf("Expire %d is outside the recommended %d-%d", soa.Expire, soaExpireMin, soaExpireMax))
+	}
+
+	if soa.Minttl < soaMinimumMin || soa.Minttl > soaMinimumMax {
+		issues = append(issues, fmt.Sprintf("Negative caching TTL (minimum) %d is outside the recommended %d-%d", soa.Minttl, soaMinimumMin, soaMinimumMax))
+	}
+
+	return issues
+}
+
+// lintSOASerial checks serials that look like the YYYYMMDDnn convention:
+// ten digits starting with a plausible year. Other serials, such as plain
+// counters or Unix timestamps, are not judged.
+func lintSOASerial(serial uint32, now time.Time) string {
+	s := strconv.FormatUint(uint64(serial), 10)
+	if len(s) != 10 {
+		return ""
+	}
+
+	year, _ := strconv.Atoi(s[:4])
+	if year < 1990 || year > 2099 {
+		return ""
+	}
+
+	date, err := time.Parse("20060102", s[:8])
+	if err != nil {
+		return fmt.Sprintf("Serial %d looks date-based but %s is not a valid date", serial, s[:8])
+	}
+	if date.After(now) {
+		return fmt.Sprintf("Serial %d is dated in the future", serial)
+	}
+
+	return ""
+}
+
+// lintSOARName checks that the RNAME encodes a mailbox, such as
+// hostmaster.example.com. for hostmaster@example.com.
+func lintSOARName(mbox string) string {
+	// miekg/dns presents a literal '@' escaped
+	mbox = strings.ReplaceAll(mbox, `\@`, "@")
+	if strings.Contains(mbox, "@") {
+		return fmt.Sprintf("RNAME %s contains '@', the first label should encode the local part", mbox)
+	}
+
+	local, domain := splitRName(strings.TrimSuffix(mbox, "."))
+	if local == "" || !strings.Contains(domain, ".") {
+		return fmt.Sprintf("RNAME %s is not a valid mailbox", mbox)
+	}
+	if _, ok := dns.IsDomainName(domain); !ok {
+		return fmt.Sprintf("RNAME %s is not a valid mailbox", mbox)
+	}
+
+	return ""
+}
+
+// rnameToMailbox converts an RNAME to an email address.
+func rnameToMailbox(mbox string) string {
+	local, domain := splitRName(strings.TrimSuffix(mbox, "."))
+	if local == "" || domain == "" {
+		return strings.TrimSuffix(mbox, ".")
+	}
+	return strings.ReplaceAll(local, `\.`, ".") + "@" + domain
+}
+
+// splitRName splits at the first unescaped dot, dots in the local part are
+// escaped as "\.".
+func splitRName(mbox string) (string, string) {
+	for i := 0; i < len(mbox); i++ {
+		switch mbox[i] {
+		case '\\':
+			i++
+		case '.':
+			return mbox[:i], mbox[i+1:]
+		}
+	}
+	return mbox, ""
+}
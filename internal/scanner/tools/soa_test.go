@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/miekg/dns"
+)
+
+func TestCheckSOA(t *testing.T) {
+	addr := startTestDNSServer(t, testZone(t,
+		"example.test. 3600 IN SOA ns1.example.test. host\\.master.example.test. 2024010101 7200 3600 1209600 3600",
+		"example.test. 3600 IN NS ns1.example.test.",
+		"example.test. 3600 IN NS ns2.example.test.",
+		"sloppy.test. 3600 IN SOA hidden.sloppy.test. admin@sloppy.test. 2024133101 300 600 300 172800",
+		"sloppy.test. 3600 IN NS ns1.sloppy.test.",
+	))
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+	ctx := context.Background()
+
+	clean := CheckSOA(ctx, resolver, "example.test")
+	if clean.Error != "" {
+		t.Fatalf("Unexpected error: %s", clean.Error)
+	}
+	if clean.Zone != "example.test" || clean.MName != "ns1.example.test" || clean.Serial != 2024010101 {
+		t.Errorf("Expected parsed SOA, got %+v", clean)
+	}
+	if clean.RName != "host.master@example.test" {
+		t.Errorf("Expected mailbox host.master@example.test, got %s", clean.RName)
+	}
+	if len(clean.Issues) != 0 {
+		t.Errorf("Expected no issues, got %v", clean.Issues)
+	}
+
+	sloppy := CheckSOA(ctx, resolver, "sloppy.test")
+	expected := []string{
+		"Refresh 300 is outside",
+		"Expire 300 is shorter than refresh 300",
+		"Negative caching TTL (minimum) 172800",
+		"Serial 2024133101 looks date-based but 20241331 is not a valid date",
+		"RNAME admin@sloppy.test. contains '@'",
+		"MNAME hidden.sloppy.test is not listed in the NS set",
+	}
+	for _, want := range expected {
+		found := false
+		for _, issue := range sloppy.Issues {
+			if strings.Contains(issue, want) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected issue %q, got %v", want, sloppy.Issues)
+		}
+	}
+}
+
+func TestLintSOATimers(t *testing.T) {
+	tests := []struct {
+		name     string
+		soa      dns.SOA
+		expected []string
+	}{
+		{
+			name: "recommended",
+			soa:  dns.SOA{Refresh: 7200, Retry: 3600, Expire: 1209600, Minttl: 3600},
+		},
+		{
+			name:     "retry not shorter than refresh",
+			soa:      dns.SOA{Refresh: 3600, Retry: 3600, Expire: 1209600, Minttl: 3600},
+			expected: []string{"Retry 3600 is not shorter than refresh 3600"},
+		},
+		{
+			name:     "expire too long",
+			soa:      dns.SOA{Refresh: 7200, Retry: 3600, Expire: 3600000, Minttl: 3600},
+			expected: []string{"Expire 3600000 is outside the recommended 1209600-2419200"},
+		},
+		{
+			name:     "retry too short",
+			soa:      dns.SOA{Refresh: 7200, Retry: 60, Expire: 1209600, Minttl: 60},
+			expected: []string{"Retry 60 is outside the recommended 120-7200", "Negative caching TTL (minimum) 60 is outside the recommended 300-86400"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			issues := lintSOATimers(&tt.soa)
+			if strings.Join(issues, "|") != strings.Join(tt.expected, "|") {
+				t.Errorf("Expected %v, got %v", tt.expected, issues)
+			}
+		})
+	}
+}
+
+func TestLintSOASerial(t *testing.T) {
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		serial uint32
+		issue  bool
+	}{
+		{serial: 2025053101, issue: false},
+		{serial: 2024022999, issue: false},
+		{serial: 2023022901, issue: true},  // not a leap year
+		{serial: 2025060201, issue: true},  // tomorrow
+		{serial: 1717200000, issue: false}, // Unix timestamp
+		{serial: 42, issue: false},
+	}
+
+	for _, tt := range tests {
+		issue := lintSOASerial(tt.serial, now)
+		if (issue != "") != tt.issue {
+			t.Errorf("Serial %d: expected issue=%v, got %q", tt.serial, tt.issue, issue)
+		}
+	}
+}
+
+func TestLintSOARName(t *testing.T) {
+	tests := []struct {
+		mbox  string
+		valid bool
+	}{
+		{mbox: "hostmaster.example.com.", valid: true},
+		{mbox: `john\.doe.example.com.`, valid: true},
+		{mbox: "hostmaster@example.com.", valid: false},
+		{mbox: "example.", valid: false},
+		{mbox: ".", valid: false},
+	}
+
+	for _, tt := range tests {
+		issue := lintSOARName(tt.mbox)
+		if (issue == "") != tt.valid {
+			t.Errorf("RNAME %s: expected valid=%v, got %q", tt.mbox, tt.valid, issue)
+		}
+	}
+}
@@ -0,0 +1,222 @@
+package tools
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/internal/logger"
+	"nsdigup/pkg/models"
+)
+
+// maxCNAMEChain bounds how many CNAME hops are followed
+const maxCNAMEChain = 10
+
+// bundledTakeoverFingerprints is the takeover fingerprint table shipped with the binary.
+//
+//go:embed data/takeover_fingerprints.json
+var bundledTakeoverFingerprints []byte
+
+// TakeoverFingerprint describes how an unclaimed resource of a cloud
+// service looks: which CNAME targets belong to it, and the body of its
+// "not found" page. Services without a body fingerprint, whose unclaimed
+// resources stop resolving instead, are caught by the NXDOMAIN check.
+type TakeoverFingerprint struct {
+	Service     string   `json:"service"`
+	CNAME       []string `json:"cname"`
+	Fingerprint []string `json:"fingerprint"`
+}
+
+// TakeoverChecker detects dangling CNAMEs that let someone else claim the
+// resource the domain points to.
+type TakeoverChecker struct {
+	fingerprints []TakeoverFingerprint
+	httpClient   *http.Client
+}
+
+// NewTakeoverChecker creates a checker using the fingerprints in file, or
+// the bundled table when file is empty or cannot be loaded.
+func NewTakeoverChecker(file string, timeout time.Duration) *TakeoverChecker {
+	checker := &TakeoverChecker{
+		httpClient: &http.Client{Timeout: timeout},
+	}
+
+	if file != "" {
+		data, err := os.ReadFile(file)
+		if err == nil {
+			checker.fingerprints, err = parseTakeoverFingerprints(data)
+		}
+		if err == nil {
+			return checker
+		}
+		logger.Get().Warn("failed to load takeover fingerprints, using bundled table",
+			slog.String("file", file),
+			slog.String("error", err.Error()))
+	}
+
+	fingerprints, err := parseTakeoverFingerprints(bundledTakeoverFingerprints)
+	if err != nil {
+		panic(fmt.Sprintf("invalid bundled takeover fingerprints: %v", err))
+	}
+	checker.fingerprints = fingerprints
+
+	return checker
+}
+
+func parseTakeoverFingerprints(data []byte) ([]TakeoverFingerprint, error) {
+	var fingerprints []TakeoverFingerprint
+	if err := json.Unmarshal(data, &fingerprints); err != nil {
+		return nil, fmt.Errorf("invalid takeover fingerprints: %w", err)
+	}
+	if len(fingerprints) == 0 {
+		return nil, fmt.Errorf("takeover fingerprints contain no services")
+	}
+	return fingerprints, nil
+}
+
+// Check follows the CNAME chain of the domain and reports a takeover risk
+// when the chain ends in NXDOMAIN, or in a known service whose response
+// shows the resource is unclaimed.
+func (c *TakeoverChecker) Check(ctx context.Context, resolver Resolver, domain string) models.TakeoverFinding {
+	finding := models.TakeoverFinding{}
+
+	chain, dangling, err := followCNAMEChain(ctx, resolver, normalizeDomain(domain))
+	if err != nil {
+		finding.Error = err.Error()
+		return finding
+	}
+	if len(chain) == 0 {
+		return finding
+	}
+	finding.CNAMEChain = chain
+
+	target := chain[len(chain)-1]
+	fingerprint := c.match(chain)
+	if fingerprint != nil {
+		finding.Service = fingerprint.Service
+	}
+
+	switch {
+	case dangling:
+		finding.Vulnerable = true
+		finding.Reason = fmt.Sprintf("CNAME target %s does not exist (NXDOMAIN)", target)
+		if fingerprint != nil {
+			finding.Reason = fmt.Sprintf("%s resource %s does not exist (NXDOMAIN)", fingerprint.Service, target)
+		}
+	case fingerprint != nil && len(fingerprint.Fingerprint) > 0:
+		if pattern := c.matchResponse(ctx, resolver, domain, fingerprint); pattern != "" {
+			finding.Vulnerable = true
+			finding.Reason = fmt.Sprintf("%s reports an unclaimed resource: %q", fingerprint.Service, pattern)
+		}
+	}
+
+	if finding.Vulnerable {
+		finding.Severity = models.SeverityHigh
+	}
+
+	return finding
+}
+
+// match returns the fingerprint of the service any CNAME in the chain points
+// into. A fingerprint's CNAME entries are domains, matched on label boundaries
+// so that "github.io" matches "org.github.io" but not "notgithub.io".
+func (c *TakeoverChecker) match(chain []string) *TakeoverFingerprint {
+	for i := len(chain) - 1; i >= 0; i-- {
+		for f := range c.fingerprints {
+			for _, target := range c.fingerprints[f].CNAME {
+				target = normalizeHost(target)
+				if chain[i] == target || strings.HasSuffix(chain[i], "."+target) {
+					return &c.fingerprints[f]
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matchResponse fetches the domain over HTTP and returns the first
+// fingerprint pattern found in the response body. The domain is resolved
+// through resolver unless the checker's client has its own transport.
+func (c *TakeoverChecker) matchResponse(ctx context.Context, resolver Resolver, domain string, fingerprint *TakeoverFingerprint) string {
+	req, err := http.NewRequestWithContext(ctx, "GET", "http://"+normalizeDomain(domain)+"/", nil)
+	if err != nil {
+		return ""
+	}
+	req.Header.Set("User-Agent", "nsdigup.sh/1.0 (Security Scanner)")
+
+	client := c.httpClient
+	if client.Transport == nil {
+		transport := newHTTPTransport(resolver, client.Timeout)
+		defer transport.CloseIdleConnections()
+		client = &http.Client{Timeout: client.Timeout, Transport: transport}
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return ""
+	}
+
+	for _, pattern := range fingerprint.Fingerprint {
+		if strings.Contains(string(body), pattern) {
+			return pattern
+		}
+	}
+	return ""
+}
+
+// followCNAMEChain returns the CNAME targets starting at name, and whether
+// the last target does not exist.
+func followCNAMEChain(ctx context.Context, resolver Resolver, name string) ([]string, bool, error) {
+	var chain []string
+	seen := map[string]bool{name: true}
+
+	current := name
+	for range maxCNAMEChain {
+		resp, err := query(ctx, resolver, current, dns.TypeCNAME)
+		if err != nil {
+			return chain, false, fmt.Errorf("CNAME lookup failed: %w", err)
+		}
+
+		next := ""
+		for _, ans := range resp.Answer {
+			if cname, ok := ans.(*dns.CNAME); ok && strings.EqualFold(cname.Hdr.Name, dns.Fqdn(current)) {
+				next = normalizeHost(cname.Target)
+				break
+			}
+		}
+		if next == "" || seen[next] {
+			break
+		}
+
+		chain = append(chain, next)
+		seen[next] = true
+		current = next
+	}
+
+	if len(chain) == 0 {
+		return nil, false, nil
+	}
+
+	// The end of the chain is dangling when it has no records at all
+	resp, err := query(ctx, resolver, current, dns.TypeA)
+	if err != nil {
+		return chain, false, fmt.Errorf("CNAME target lookup failed: %w", err)
+	}
+
+	return chain, resp.Rcode == dns.RcodeNameError, nil
+}
@@ -0,0 +1,112 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"nsdigup/pkg/models"
+)
+
+func TestTakeoverChecker_Check(t *testing.T) {
+	addr := startTestDNSServer(t, testZone(t,
+		"plain.example.test. 300 IN A 192.0.2.10",
+		"dangling.example.test. 300 IN CNAME gone.azurewebsites.net.",
+		"healthy.example.test. 300 IN CNAME app.example.net.",
+		"app.example.net. 300 IN A 192.0.2.20",
+		"pages.example.test. 300 IN CNAME www.example.test.",
+		"www.example.test. 300 IN CNAME org.github.io.",
+		"org.github.io. 300 IN A 192.0.2.30",
+		"loop.example.test. 300 IN CNAME loop.example.test.",
+		"lookalike.example.test. 300 IN CNAME github.io.example.net.",
+		"github.io.example.net. 300 IN A 192.0.2.40",
+		"prefixed.example.test. 300 IN CNAME notgithub.io.",
+		"notgithub.io. 300 IN A 192.0.2.50",
+	))
+
+	// Every HTTP request lands on the unclaimed GitHub Pages page
+	pages := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+		fmt.Fprint(w, "<h1>404</h1><p>There isn't a GitHub Pages site here.</p>")
+	}))
+	defer pages.Close()
+
+	checker := NewTakeoverChecker("", time.Second)
+	checker.httpClient.Transport = &http.Transport{
+		DialContext: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			return (&net.Dialer{}).DialContext(ctx, network, pages.Listener.Addr().String())
+		},
+	}
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+	ctx := context.Background()
+
+	tests := []struct {
+		domain     string
+		vulnerable bool
+		service    string
+		chainLen   int
+	}{
+		{domain: "plain.example.test", vulnerable: false, chainLen: 0},
+		{domain: "dangling.example.test", vulnerable: true, service: "Microsoft Azure", chainLen: 1},
+		{domain: "healthy.example.test", vulnerable: false, chainLen: 1},
+		{domain: "pages.example.test", vulnerable: true, service: "GitHub Pages", chainLen: 2},
+		{domain: "loop.example.test", vulnerable: false, chainLen: 0},
+		{domain: "lookalike.example.test", vulnerable: false, chainLen: 1},
+		{domain: "prefixed.example.test", vulnerable: false, chainLen: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			finding := checker.Check(ctx, resolver, tt.domain)
+
+			if finding.Error != "" {
+				t.Fatalf("Unexpected error: %s", finding.Error)
+			}
+
+			if finding.Vulnerable != tt.vulnerable {
+				t.Errorf("Expected vulnerable=%v, got %+v", tt.vulnerable, finding)
+			}
+
+			if finding.Service != tt.service {
+				t.Errorf("Expected service %q, got %q", tt.service, finding.Service)
+			}
+
+			if len(finding.CNAMEChain) != tt.chainLen {
+				t.Errorf("Expected chain of %d, got %v", tt.chainLen, finding.CNAMEChain)
+			}
+
+			if tt.vulnerable && finding.Severity != models.SeverityHigh {
+				t.Errorf("Expected high severity, got %q", finding.Severity)
+			}
+		})
+	}
+}
+
+func TestNewTakeoverChecker_FingerprintFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fingerprints.json")
+	table := `[{"service": "Internal PaaS", "cname": ["apps.internal.test"], "fingerprint": ["no app configured"]}]`
+	if err := os.WriteFile(path, []byte(table), 0o600); err != nil {
+		t.Fatalf("Failed to write fingerprints: %v", err)
+	}
+
+	checker := NewTakeoverChecker(path, time.Second)
+	if fp := checker.match([]string{"shop.apps.internal.test"}); fp == nil || fp.Service != "Internal PaaS" {
+		t.Errorf("Expected custom fingerprint to match, got %+v", fp)
+	}
+	if fp := checker.match([]string{"org.github.io"}); fp != nil {
+		t.Errorf("Expected the file to replace the bundled table, got %+v", fp)
+	}
+
+	// Invalid files fall back to the bundled table
+	fallback := NewTakeoverChecker(filepath.Join(t.TempDir(), "missing.json"), time.Second)
+	if fp := fallback.match([]string{"bucket.s3.amazonaws.com"}); fp == nil || fp.Service != "AWS S3" {
+		t.Errorf("Expected bundled fingerprints, got %+v", fp)
+	}
+}
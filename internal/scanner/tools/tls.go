@@ -45,7 +45,7 @@ var weakCipherPatterns = []string{
 }
 
 // AnalyzeTLS performs comprehensive TLS protocol and cipher suite analysis
-func AnalyzeTLS(ctx context.Context, domain string, timeout time.Duration) TLSAnalysisResult {
+func AnalyzeTLS(ctx context.Context, resolver Resolver, domain string, timeout time.Duration) TLSAnalysisResult {
 	result := TLSAnalysisResult{
 		TLSVersions:      []string{},
 		WeakTLSVersions:  []string{},
@@ -79,11 +79,7 @@ func AnalyzeTLS(ctx context.Context, domain string, timeout time.Duration) TLSAn
 			InsecureSkipVerify: true, // We're testing support, not validating certs
 		}
 
-		dialer := &net.Dialer{
-			Timeout: timeout,
-		}
-
-		conn, err := tls.DialWithDialer(dialer, "tcp", target, config)
+		conn, err := dialTLS(ctx, resolver, target, config, timeout)
 		if err != nil {
 			// This version not supported or connection failed
 			continue
@@ -121,7 +117,7 @@ func AnalyzeTLS(ctx context.Context, domain string, timeout time.Duration) TLSAn
 	// Enumerate cipher suites more thoroughly using TLS 1.2
 	// (TLS 1.3 has a fixed set of cipher suites)
 	if supportedVersions[tls.VersionTLS12] {
-		detectedCiphers := probeCipherSuites(target, timeout)
+		detectedCiphers := probeCipherSuites(ctx, resolver, target, timeout)
 		for _, cipher := range detectedCiphers {
 			if _, exists := cipherSuiteNames[cipher]; !exists {
 				allCipherSuites = append(allCipherSuites, cipher)
@@ -144,7 +140,7 @@ func AnalyzeTLS(ctx context.Context, domain string, timeout time.Duration) TLSAn
 }
 
 // probeCipherSuites attempts to detect supported cipher suites
-func probeCipherSuites(target string, timeout time.Duration) []uint16 {
+func probeCipherSuites(ctx context.Context, resolver Resolver, target string, timeout time.Duration) []uint16 {
 	var detected []uint16
 
 	// Test with default cipher suites first
@@ -154,11 +150,7 @@ func probeCipherSuites(target string, timeout time.Duration) []uint16 {
 		InsecureSkipVerify: true,
 	}
 
-	dialer := &net.Dialer{
-		Timeout: timeout,
-	}
-
-	conn, err := tls.DialWithDialer(dialer, "tcp", target, config)
+	conn, err := dialTLS(ctx, resolver, target, config, timeout)
 	if err != nil {
 		return detected
 	}
@@ -2,90 +2,66 @@ package tools
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"log/slog"
 	"time"
 
 	"github.com/likexian/whois"
 	whoisparser "github.com/likexian/whois-parser"
 
+	"nsdigup/internal/logger"
 	"nsdigup/pkg/models"
 )
 
-// WHOISResult contains the parsed WHOIS information
+// WHOISResult contains the parsed registration information
 type WHOISResult struct {
-	Registrar     string
-	Owner         string
-	ExpiresAt     time.Time
-	ExpiresInDays int
-	Error         error
+	Registrar      string
+	Owner          string
+	PrivacyService string
+	AbuseEmail     string
+	AbusePhone     string
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	ExpiresAt      time.Time
+	ExpiresInDays  int
+	Statuses       []string
+	Source         string
+	Error          error
 }
 
-// CheckWHOIS fetches and parses WHOIS data for a domain
-func CheckWHOIS(ctx context.Context, domain string, timeout time.Duration) WHOISResult {
+// CheckWHOIS fetches registration data for the registrable domain of domain.
+// Names without one, such as IP addresses, are looked up unchanged. When an
+// RDAP client is given it is tried first, with port-43 WHOIS as the fallback.
+func CheckWHOIS(ctx context.Context, resolver Resolver, rdap *RDAPClient, domain string, timeout time.Duration) WHOISResult {
 	domain = normalizeDomain(domain)
+	if registrable := RegistrableDomain(domain); registrable != "" {
+		domain = registrable
+	}
 
-	// Create a channel for the WHOIS operation with timeout
+	// Create a channel for the lookup with timeout
 	done := make(chan WHOISResult, 1)
 
 	go func() {
-		// Fetch raw WHOIS data
-		rawData, err := whois.Whois(domain)
-		if err != nil {
-			done <- WHOISResult{
-				Error: fmt.Errorf("WHOIS fetch failed: %w", err),
-			}
-			return
-		}
-
-		// Parse WHOIS data
-		parsed, err := whoisparser.Parse(rawData)
-		if err != nil {
-			done <- WHOISResult{
-				Error: fmt.Errorf("WHOIS parse failed: %w", err),
+		if rdap != nil {
+			result, err := rdap.Lookup(ctx, domain)
+			if err == nil || errors.Is(err, ErrDomainNotFound) {
+				result.Error = err
+				done <- result
+				return
 			}
-			return
-		}
-
-		// Extract registrar
-		registrar := ""
-		if parsed.Registrar != nil {
-			registrar = parsed.Registrar.Name
+			logger.GetFromContext(ctx, logger.Get()).Debug("RDAP lookup failed, falling back to WHOIS",
+				slog.String("domain", domain),
+				slog.String("error", err.Error()))
 		}
 
-		// Extract owner (registrant organization or name)
-		owner := ""
-		if parsed.Registrant != nil {
-			if parsed.Registrant.Organization != "" {
-				owner = parsed.Registrant.Organization
-			} else if parsed.Registrant.Name != "" {
-				owner = parsed.Registrant.Name
-			}
-		}
-
-		// Calculate expiration date and days using shared logic
-		var expiresAt time.Time
-		expiresInDays := 0
-		if parsed.Domain != nil && parsed.Domain.ExpirationDate != "" {
-			expiryDate, err := parseDate(parsed.Domain.ExpirationDate)
-			if err == nil {
-				expiresAt = expiryDate
-				expiresInDays = models.CalculateDaysUntilExpiration(expiryDate)
-			}
-		}
-
-		done <- WHOISResult{
-			Registrar:     registrar,
-			Owner:         owner,
-			ExpiresAt:     expiresAt,
-			ExpiresInDays: expiresInDays,
-			Error:         nil,
-		}
+		done <- lookupWHOIS(ctx, resolver, domain, timeout)
 	}()
 
 	timer := time.NewTimer(timeout)
 	defer timer.Stop()
 
-	// Wait for result or timeout (3 seconds as per plan)
+	// Wait for result or timeout
 	select {
 	case <-ctx.Done():
 		return WHOISResult{
@@ -95,9 +71,75 @@ func CheckWHOIS(ctx context.Context, domain string, timeout time.Duration) WHOIS
 		return res
 	case <-timer.C:
 		return WHOISResult{
-			Error: fmt.Errorf("WHOIS query timeout after 3s"),
+			Error: fmt.Errorf("WHOIS query timeout after %s", timeout),
+		}
+	}
+}
+
+// lookupWHOIS fetches and parses port-43 WHOIS data for a domain, resolving
+// the WHOIS servers through resolver
+func lookupWHOIS(ctx context.Context, resolver Resolver, domain string, timeout time.Duration) WHOISResult {
+	// Fetch raw WHOIS data
+	client := whois.NewClient().
+		SetDialer(whoisDialer{ctx: ctx, dial: newDialer(resolver, timeout)}).
+		SetTimeout(timeout)
+	rawData, err := client.Whois(domain)
+	if err != nil {
+		return WHOISResult{
+			Error: fmt.Errorf("WHOIS fetch failed: %w", err),
+		}
+	}
+
+	// Parse WHOIS data
+	parsed, err := whoisparser.Parse(rawData)
+	if err != nil {
+		if errors.Is(err, whoisparser.ErrNotFoundDomain) {
+			return WHOISResult{Source: "whois", Error: ErrDomainNotFound}
+		}
+		return WHOISResult{
+			Error: fmt.Errorf("WHOIS parse failed: %w", err),
 		}
 	}
+
+	result := WHOISResult{Source: "whois"}
+
+	// Extract registrar, whose contact details are its abuse contact
+	if parsed.Registrar != nil {
+		result.Registrar = parsed.Registrar.Name
+		result.AbuseEmail = parsed.Registrar.Email
+		result.AbusePhone = parsed.Registrar.Phone
+	}
+
+	// Extract owner (registrant organization or name)
+	if parsed.Registrant != nil {
+		result.setOwner(parsed.Registrant.Organization, parsed.Registrant.Name)
+	}
+
+	if parsed.Domain != nil {
+		result.Statuses = parsed.Domain.Status
+
+		// Calculate expiration date and days using shared logic
+		if parsed.Domain.ExpirationDate != "" {
+			if expiryDate, err := parseDate(parsed.Domain.ExpirationDate); err == nil {
+				result.ExpiresAt = expiryDate
+				result.ExpiresInDays = models.CalculateDaysUntilExpiration(expiryDate)
+			}
+		}
+
+		if parsed.Domain.CreatedDate != "" {
+			if createdDate, err := parseDate(parsed.Domain.CreatedDate); err == nil {
+				result.CreatedAt = createdDate
+			}
+		}
+
+		if parsed.Domain.UpdatedDate != "" {
+			if updatedDate, err := parseDate(parsed.Domain.UpdatedDate); err == nil {
+				result.UpdatedAt = updatedDate
+			}
+		}
+	}
+
+	return result
 }
 
 // parseDate attempts to parse various date formats commonly found in WHOIS data
@@ -105,6 +147,7 @@ func parseDate(dateStr string) (time.Time, error) {
 	// Common WHOIS date formats
 	formats := []string{
 		"2006-01-02T15:04:05Z",
+		"2006-01-02T15:04:05-0700",
 		"2006-01-02 15:04:05",
 		"2006-01-02",
 		"02-Jan-2006",
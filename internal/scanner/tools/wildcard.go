@@ -0,0 +1,86 @@
+package tools
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/miekg/dns"
+
+	"nsdigup/pkg/models"
+)
+
+// wildcardProbeLabel returns a random label that no one would create on purpose.
+var wildcardProbeLabel = func() string {
+	buf := make([]byte, 8)
+	rand.Read(buf)
+	return "nsdigup-" + hex.EncodeToString(buf)
+}
+
+// CheckWildcard resolves a random label under the domain. Any A, AAAA,
+// CNAME or MX answer for it can only come from a wildcard record.
+func CheckWildcard(ctx context.Context, resolver Resolver, domain string) models.WildcardDNS {
+	probe := wildcardProbeLabel() + "." + normalizeDomain(domain)
+	result := models.WildcardDNS{Probe: probe}
+
+	for _, qtype := range []uint16{dns.TypeA, dns.TypeAAAA, dns.TypeCNAME, dns.TypeMX} {
+		resp, err := query(ctx, resolver, probe, qtype)
+		if err != nil {
+			result.Error = fmt.Sprintf("%s lookup failed: %v", dns.TypeToString[qtype], err)
+			return result
+		}
+		if resp.Rcode != dns.RcodeSuccess {
+			continue
+		}
+
+		for _, ans := range resp.Answer {
+			switch rr := ans.(type) {
+			case *dns.A:
+				result.A = appendUnique(result.A, rr.A.String())
+			case *dns.AAAA:
+				result.AAAA = appendUnique(result.AAAA, rr.AAAA.String())
+			case *dns.CNAME:
+				if strings.EqualFold(rr.Hdr.Name, dns.Fqdn(probe)) {
+					result.CNAME = normalizeHost(rr.Target)
+				}
+			case *dns.MX:
+				result.MX = appendUnique(result.MX, normalizeHost(rr.Mx))
+			}
+		}
+	}
+
+	result.Detected = len(result.A) > 0 || len(result.AAAA) > 0 || result.CNAME != "" || len(result.MX) > 0
+	return result
+}
+
+// CorrelateWildcardCertificate records whether the certificate served for
+// the domain is a wildcard that is also valid for the names the wildcard
+// DNS record answers, so any of them can be served over trusted HTTPS.
+func CorrelateWildcardCertificate(wildcard *models.WildcardDNS, certs models.Certificates) {
+	if !wildcard.Detected || !certs.IsWildcard {
+		return
+	}
+
+	names := certs.SubjectAltNames
+	if len(names) == 0 && certs.CommonName != "" {
+		names = []string{certs.CommonName}
+	}
+
+	for _, name := range names {
+		name = strings.ToLower(name)
+		if strings.HasPrefix(name, "*.") && matchHostname(wildcard.Probe, name) {
+			wildcard.CoveredByCertificate = true
+			return
+		}
+	}
+}
+
+func appendUnique(list []string, value string) []string {
+	if slices.Contains(list, value) {
+		return list
+	}
+	return append(list, value)
+}
@@ -0,0 +1,108 @@
+package tools
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"nsdigup/pkg/models"
+)
+
+func TestCheckWildcard(t *testing.T) {
+	addr := startTestDNSServer(t, testZone(t,
+		"example.test. 300 IN A 192.0.2.10",
+		"probe.wild.test. 300 IN A 192.0.2.20",
+		"probe.wild.test. 300 IN AAAA 2001:db8::20",
+		"probe.wild.test. 300 IN MX 10 mail.wild.test.",
+		"probe.alias.test. 300 IN CNAME lb.example.net.",
+	))
+
+	original := wildcardProbeLabel
+	wildcardProbeLabel = func() string { return "probe" }
+	defer func() { wildcardProbeLabel = original }()
+
+	resolver := NewUpstreamResolver([]string{addr}, time.Second)
+	ctx := context.Background()
+
+	none := CheckWildcard(ctx, resolver, "example.test")
+	if none.Detected || none.Error != "" {
+		t.Errorf("Expected no wildcard, got %+v", none)
+	}
+
+	wild := CheckWildcard(ctx, resolver, "wild.test")
+	if !wild.Detected {
+		t.Fatalf("Expected wildcard, got %+v", wild)
+	}
+	if wild.Probe != "probe.wild.test" {
+		t.Errorf("Expected probe name probe.wild.test, got %s", wild.Probe)
+	}
+	if !slices.Equal(wild.A, []string{"192.0.2.20"}) || !slices.Equal(wild.AAAA, []string{"2001:db8::20"}) {
+		t.Errorf("Expected wildcard addresses, got A=%v AAAA=%v", wild.A, wild.AAAA)
+	}
+	if !slices.Equal(wild.MX, []string{"mail.wild.test"}) {
+		t.Errorf("Expected wildcard MX, got %v", wild.MX)
+	}
+
+	alias := CheckWildcard(ctx, resolver, "alias.test")
+	if !alias.Detected || alias.CNAME != "lb.example.net" {
+		t.Errorf("Expected wildcard CNAME lb.example.net, got %+v", alias)
+	}
+}
+
+func TestCheckWildcard_RandomLabel(t *testing.T) {
+	first, second := wildcardProbeLabel(), wildcardProbeLabel()
+	if first == second {
+		t.Errorf("Expected distinct probe labels, got %s twice", first)
+	}
+}
+
+func TestCorrelateWildcardCertificate(t *testing.T) {
+	tests := []struct {
+		name     string
+		wildcard models.WildcardDNS
+		certs    models.Certificates
+		expected bool
+	}{
+		{
+			name:     "wildcard certificate covers probe",
+			wildcard: models.WildcardDNS{Detected: true, Probe: "probe.example.com"},
+			certs:    models.Certificates{IsWildcard: true, SubjectAltNames: []string{"example.com", "*.example.com"}},
+			expected: true,
+		},
+		{
+			name:     "common name fallback",
+			wildcard: models.WildcardDNS{Detected: true, Probe: "probe.example.com"},
+			certs:    models.Certificates{IsWildcard: true, CommonName: "*.EXAMPLE.com"},
+			expected: true,
+		},
+		{
+			name:     "wildcard for another zone",
+			wildcard: models.WildcardDNS{Detected: true, Probe: "probe.example.com"},
+			certs:    models.Certificates{IsWildcard: true, SubjectAltNames: []string{"*.cdn.example.net"}},
+			expected: false,
+		},
+		{
+			name:     "no wildcard certificate",
+			wildcard: models.WildcardDNS{Detected: true, Probe: "probe.example.com"},
+			certs:    models.Certificates{SubjectAltNames: []string{"example.com"}},
+			expected: false,
+		},
+		{
+			name:     "no wildcard DNS",
+			wildcard: models.WildcardDNS{Probe: "probe.example.com"},
+			certs:    models.Certificates{IsWildcard: true, SubjectAltNames: []string{"*.example.com"}},
+			expected: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wildcard := tt.wildcard
+			CorrelateWildcardCertificate(&wildcard, tt.certs)
+			if wildcard.CoveredByCertificate != tt.expected {
+				t.Errorf("Expected covered=%v, got %v", tt.expected, wildcard.CoveredByCertificate)
+			}
+		})
+	}
+}
@@ -94,5 +94,5 @@ func extractDomain(path string) string {
 	if path == "" {
 		return ""
 	}
-	return path
+	return toASCIIDomain(path)
 }
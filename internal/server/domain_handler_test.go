@@ -173,6 +173,66 @@ func TestHandler_MultipleDomains(t *testing.T) {
 	}
 }
 
+func TestHandler_UnicodeDomain(t *testing.T) {
+	mockReport := &models.Report{
+		Identity: models.Identity{IP: "192.168.1.1"},
+	}
+	mock := &mockScanner{report: mockReport}
+
+	cfg := &config.Config{
+		App:   config.AppConfig{Host: "0.0.0.0", Port: 8080, AdvertisedAddress: "http://localhost:8080"},
+		Cache: config.CacheConfig{Mode: config.CacheModeMem, TTL: 1 * time.Hour},
+	}
+	handler := NewHandler(cfg)
+	handler.scanner = mock
+
+	// Both forms are the same domain, scanned once as its A-label
+	for _, path := range []string{"/bücher.de", "/b%C3%BCcher.de", "/xn--bcher-kva.de"} {
+		req := httptest.NewRequest("GET", path, nil)
+		req.Header.Set("Accept", "application/json")
+		w := httptest.NewRecorder()
+		handler.Router().ServeHTTP(w, req)
+
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200 for %s, got %d", path, w.Code)
+		}
+
+		var report models.Report
+		if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+			t.Fatalf("Failed to parse JSON: %v", err)
+		}
+		if report.Target != "xn--bcher-kva.de" {
+			t.Errorf("Expected A-label target for %s, got %q", path, report.Target)
+		}
+	}
+
+	if mock.calls != 1 {
+		t.Errorf("Expected 1 scanner call, got %d", mock.calls)
+	}
+}
+
+func TestIsDomainPath(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"/example.com", true},
+		{"/example.com:8443", true},
+		{"/192.168.1.1", true},
+		{"/bücher.de", true},
+		{"/例え.jp", true},
+		{"/-invalid-.com", false},
+		{"/exa mple.com", false},
+		{"/", false},
+	}
+
+	for _, tt := range tests {
+		if got := isDomainPath(tt.path); got != tt.expected {
+			t.Errorf("Expected isDomainPath(%q) = %v, got %v", tt.path, tt.expected, got)
+		}
+	}
+}
+
 func TestHandler_ScannerError(t *testing.T) {
 	mock := &mockScanner{
 		err: context.DeadlineExceeded,
@@ -11,6 +11,7 @@ import (
 	"nsdigup/internal/logger"
 	"nsdigup/internal/renderer"
 	"nsdigup/internal/scanner"
+	"nsdigup/internal/scanner/tools"
 )
 
 type Handler struct {
@@ -42,7 +43,7 @@ func NewHandler(cfg *config.Config) *Handler {
 	}
 
 	return &Handler{
-		scanner:      scanner.NewScanner(),
+		scanner:      scanner.NewScanner(cfg),
 		cache:        store,
 		jsonRenderer: renderer.NewJSONRenderer(),
 		ansiRenderer: renderer.NewANSIRenderer(),
@@ -103,14 +104,30 @@ func (h *Handler) getOutputFormat(r *http.Request) OutputFormat {
 	return OutputFormatANSI
 }
 
-// domainRegex validates domain names and optional ports
-// Matches: example.com, sub.example.com, example.com:8080, 192.168.1.1
+// domainRegex validates domain names and optional ports, after conversion to A-labels
+// Matches: example.com, sub.example.com, example.com:8080, 192.168.1.1, xn--bcher-kva.de
 var domainRegex = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9\-\.]*[a-zA-Z0-9])?(\:[0-9]+)?$`)
 
 func isDomainPath(path string) bool {
-	domain := strings.TrimPrefix(path, "/")
+	domain := extractDomain(path)
 	if domain == "" {
 		return false
 	}
 	return domainRegex.MatchString(domain)
 }
+
+// toASCIIDomain converts a Unicode domain, with an optional port, to its
+// A-label form so that "bücher.de" is scanned as "xn--bcher-kva.de".
+// It returns "" for names that are not valid IDNA2008 domains.
+func toASCIIDomain(domain string) string {
+	host, port := domain, ""
+	if i := strings.LastIndex(domain, ":"); i >= 0 {
+		host, port = domain[:i], domain[i:]
+	}
+
+	ascii, err := tools.ToASCII(host)
+	if err != nil {
+		return ""
+	}
+	return ascii + port
+}
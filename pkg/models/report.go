@@ -6,27 +6,311 @@ type Report struct {
 	Target       string       `json:"target"`
 	Timestamp    time.Time    `json:"timestamp"`
 	Identity     Identity     `json:"identity"`
+	Records      []DNSRecord  `json:"dns_records,omitempty"`
 	Certificates Certificates `json:"certificates"`
 	Findings     Findings     `json:"findings"`
 }
 
+// DNSRecord is one resource record of the domain's record inventory. Value
+// is the record data in zone file presentation format.
+type DNSRecord struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	TTL   uint32 `json:"ttl"`
+	Value string `json:"value"`
+}
+
 type Identity struct {
-	IP            string    `json:"ip_address"`
-	Registrar     string    `json:"registrar"`
-	Owner         string    `json:"owner"`
+	IP string `json:"ip_address"`
+
+	// Every A/AAAA address and whether the domain works over IPv6
+	Addresses     []Address     `json:"addresses,omitempty"`
+	IPv6Readiness IPv6Readiness `json:"ipv6_readiness"`
+
+	// Registrable domain (eTLD+1) of the target per the Public Suffix List
+	RegistrableDomain string `json:"registrable_domain,omitempty"`
+
+	// Both forms of an internationalized domain name, and homograph warnings
+	IDN *IDN `json:"idn,omitempty"`
+
+	Registrar          string    `json:"registrar"`
+	Owner              string    `json:"owner"`
+	PrivacyService     string    `json:"privacy_service,omitempty"`
+	AbuseEmail         string    `json:"abuse_email,omitempty"`
+	AbusePhone         string    `json:"abuse_phone,omitempty"`
+	RegistrationSource string    `json:"registration_source,omitempty"`
+	ExpiresAt          time.Time `json:"expires_at,omitempty"`
+	ExpiresInDays      int       `json:"expires_in_days,omitempty"`
+	CreatedAt          time.Time `json:"created_at,omitempty"`
+	UpdatedAt          time.Time `json:"updated_at,omitempty"`
+	AgeDays            int       `json:"age_days,omitempty"`
+	NewlyRegistered    bool      `json:"newly_registered,omitempty"`
+	RecentlyUpdated    bool      `json:"recently_updated,omitempty"`
+	Status             string    `json:"status,omitempty"`
+	Nameservers        []string  `json:"nameservers"`
+
+	// EPP status codes of the registration, such as clientTransferProhibited
+	DomainStatus DomainStatus `json:"domain_status"`
+
+	// Authoritative nameserver consistency
+	NameserverAnalysis NameserverAnalysis `json:"nameserver_analysis"`
+
+	// Resilience of the nameserver set against single points of failure
+	NameserverDiversity NameserverDiversity `json:"nameserver_diversity"`
+
+	// Registration of the domains the nameservers are under
+	NameserverDomains []NameserverDomain `json:"nameserver_domains,omitempty"`
+
+	// Wildcard records answering for names that were never created
+	Wildcard WildcardDNS `json:"wildcard"`
+
+	// DNSSEC validation
+	DNSSECEnabled bool           `json:"dnssec_enabled,omitempty"`
+	DNSSECValid   bool           `json:"dnssec_valid,omitempty"`
+	DNSSECError   string         `json:"dnssec_error,omitempty"`
+	DNSSECChain   []DNSSECZone   `json:"dnssec_chain,omitempty"`
+	DNSSECDenial  *DNSSECDenial  `json:"dnssec_denial,omitempty"`
+	DNSSECSigning *DNSSECSigning `json:"dnssec_signing,omitempty"`
+
+	// CAA records
+	CAARecords []string  `json:"caa_records,omitempty"`
+	CAAMissing bool      `json:"caa_missing,omitempty"`
+	CAAPolicy  CAAPolicy `json:"caa_policy"`
+}
+
+// IDN describes an internationalized domain name. Lookalike is the ASCII
+// name the domain can pass for, when its labels are made of confusable
+// characters.
+type IDN struct {
+	ASCII     string   `json:"ascii"`
+	Unicode   string   `json:"unicode"`
+	Scripts   []string `json:"scripts,omitempty"`
+	Lookalike string   `json:"lookalike,omitempty"`
+	Issues    []string `json:"issues,omitempty"`
+}
+
+// DomainStatus holds the EPP status codes of the registration (RFC 5731
+// section 2.3) and what they mean for the safety of the domain. Critical
+// lists the statuses that put the registration itself at risk.
+type DomainStatus struct {
+	Codes             []string `json:"codes,omitempty"`
+	TransferProtected bool     `json:"transfer_protected"`
+	RegistryLock      bool     `json:"registry_lock"`
+	Critical          []string `json:"critical,omitempty"`
+	Issues            []string `json:"issues,omitempty"`
+}
+
+// Address is one A or AAAA record of the domain and how it responds.
+type Address struct {
+	IP              string `json:"ip"`
+	Family          string `json:"family"`
+	TTL             uint32 `json:"ttl"`
+	Reachable443    bool   `json:"reachable_443"`
+	Reachable80     bool   `json:"reachable_80"`
+	CertFingerprint string `json:"cert_sha256,omitempty"`
+
+	// Reverse DNS, forward-confirmed when a PTR name resolves back to IP
+	PTR    []string `json:"ptr,omitempty"`
+	FCrDNS bool     `json:"fcrdns"`
+
+	// Hosting network, when a local MMDB database is configured
+	Network *Network `json:"network,omitempty"`
+}
+
+// Network describes who hosts an IP address.
+type Network struct {
+	ASN          uint32 `json:"asn,omitempty"`
+	Organization string `json:"organization,omitempty"`
+	Prefix       string `json:"prefix,omitempty"`
+	Country      string `json:"country,omitempty"`
+}
+
+// IPv6Readiness tells whether the domain is served over IPv6 as well as over IPv4.
+type IPv6Readiness struct {
+	HasAAAA         bool `json:"has_aaaa"`
+	Reachable       bool `json:"reachable"`
+	SameCertificate bool `json:"same_certificate"`
+	Ready           bool `json:"ready"`
+}
+
+// NameserverAnalysis is the result of querying every authoritative nameserver
+// of the zone directly and comparing their answers with the parent delegation.
+type NameserverAnalysis struct {
+	Servers         []NameserverCheck `json:"servers,omitempty"`
+	ParentNS        []string          `json:"parent_ns,omitempty"`
+	ChildNS         []string          `json:"child_ns,omitempty"`
+	OnlyAtParent    []string          `json:"only_at_parent,omitempty"`
+	OnlyAtChild     []string          `json:"only_at_child,omitempty"`
+	SerialMismatch  bool              `json:"serial_mismatch"`
+	LameDelegations []string          `json:"lame_delegations,omitempty"`
+	Unreachable     []string          `json:"unreachable,omitempty"`
+	AXFRAllowed     []string          `json:"axfr_allowed,omitempty"`
+	OpenResolvers   []string          `json:"open_resolvers,omitempty"`
+	EDNSFailures    []string          `json:"edns_failures,omitempty"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// NameserverDiversity scores how resilient the nameserver set is against a
+// single network, operator, domain or TLD failing. Score counts the axes on
+// which the nameservers are diverse, out of MaxScore.
+type NameserverDiversity struct {
+	Nameservers   int      `json:"nameservers"`
+	IPv4Prefixes  []string `json:"ipv4_prefixes,omitempty"`
+	IPv6Prefixes  []string `json:"ipv6_prefixes,omitempty"`
+	ASNs          []uint32 `json:"asns,omitempty"`
+	ParentDomains []string `json:"parent_domains,omitempty"`
+	TLDs          []string `json:"tlds,omitempty"`
+	Score         int      `json:"score"`
+	MaxScore      int      `json:"max_score"`
+	Issues        []string `json:"issues,omitempty"`
+}
+
+// NameserverDomain is the registration of a domain that nameservers of the
+// target are under. If it is unregistered or lapses, whoever registers it
+// controls resolution of the target.
+type NameserverDomain struct {
+	Domain        string    `json:"domain"`
+	Nameservers   []string  `json:"nameservers"`
+	Registered    bool      `json:"registered"`
 	ExpiresAt     time.Time `json:"expires_at,omitempty"`
 	ExpiresInDays int       `json:"expires_in_days,omitempty"`
 	Status        string    `json:"status,omitempty"`
-	Nameservers   []string  `json:"nameservers"`
+	Error         string    `json:"error,omitempty"`
+}
 
-	// DNSSEC validation
-	DNSSECEnabled bool   `json:"dnssec_enabled,omitempty"`
-	DNSSECValid   bool   `json:"dnssec_valid,omitempty"`
-	DNSSECError   string `json:"dnssec_error,omitempty"`
+// NameserverCheck holds what a single authoritative nameserver answered.
+type NameserverCheck struct {
+	Host          string       `json:"host"`
+	Addresses     []string     `json:"addresses,omitempty"`
+	Reachable     bool         `json:"reachable"`
+	Authoritative bool         `json:"authoritative"`
+	OpenResolver  bool         `json:"open_resolver"`
+	Serial        uint32       `json:"serial,omitempty"`
+	NS            []string     `json:"ns,omitempty"`
+	Networks      []Network    `json:"networks,omitempty"`
+	ZoneTransfer  ZoneTransfer `json:"zone_transfer"`
+	EDNS          []EDNSProbe  `json:"edns,omitempty"`
+	Error         string       `json:"error,omitempty"`
+}
 
-	// CAA records
-	CAARecords []string `json:"caa_records,omitempty"`
-	CAAMissing bool     `json:"caa_missing,omitempty"`
+// EDNSProbe is the result of one EDNS or TCP compliance test against a nameserver.
+type EDNSProbe struct {
+	Test         string `json:"test"`
+	Passed       bool   `json:"passed"`
+	Inconclusive bool   `json:"inconclusive,omitempty"`
+	Detail       string `json:"detail,omitempty"`
+}
+
+// ZoneTransfer is the outcome of an AXFR attempt against one nameserver.
+// Records are only kept when explicitly configured.
+type ZoneTransfer struct {
+	Allowed     bool     `json:"allowed"`
+	RecordCount int      `json:"record_count,omitempty"`
+	Records     []string `json:"records,omitempty"`
+	Error       string   `json:"error,omitempty"`
+}
+
+// WildcardDNS is what a random, unguessable name under the domain resolves to.
+type WildcardDNS struct {
+	Detected             bool     `json:"detected"`
+	Probe                string   `json:"probe,omitempty"`
+	A                    []string `json:"a,omitempty"`
+	AAAA                 []string `json:"aaaa,omitempty"`
+	CNAME                string   `json:"cname,omitempty"`
+	MX                   []string `json:"mx,omitempty"`
+	CoveredByCertificate bool     `json:"covered_by_certificate"`
+	Error                string   `json:"error,omitempty"`
+}
+
+// CAAPolicy is the parsed CAA record set that applies to the domain,
+// cross-checked against the certificate currently served.
+type CAAPolicy struct {
+	Domain    string      `json:"domain,omitempty"`
+	Issue     []CAAIssuer `json:"issue,omitempty"`
+	IssueWild []CAAIssuer `json:"issuewild,omitempty"`
+	IODEF     []string    `json:"iodef,omitempty"`
+
+	// CA of the served certificate, when found in the bundled table
+	CertificateCA string   `json:"certificate_ca,omitempty"`
+	Violations    []string `json:"violations,omitempty"`
+}
+
+// CAAIssuer is one issue or issuewild property. An empty domain forbids
+// issuance, parameters hold accounturi, validationmethods and the like.
+type CAAIssuer struct {
+	Domain     string            `json:"domain"`
+	Parameters map[string]string `json:"parameters,omitempty"`
+	Critical   bool              `json:"critical,omitempty"`
+}
+
+// DNSSECSigning lists the keys, DS records and signatures of the signed
+// zone holding the domain.
+type DNSSECSigning struct {
+	Zone       string            `json:"zone"`
+	Keys       []DNSSECKey       `json:"keys,omitempty"`
+	DS         []DNSSECDS        `json:"ds,omitempty"`
+	Signatures []DNSSECSignature `json:"signatures,omitempty"`
+	Rollover   DNSSECRollover    `json:"rollover"`
+	Issues     []string          `json:"issues,omitempty"`
+}
+
+// DNSSECRollover compares the parent's DS records with the zone's keys and
+// the CDS/CDNSKEY records it publishes for the parent. Status is empty when
+// the zone publishes no CDS/CDNSKEY.
+type DNSSECRollover struct {
+	Status          string   `json:"status,omitempty"`
+	CDS             []uint16 `json:"cds_key_tags,omitempty"`
+	CDNSKEY         []uint16 `json:"cdnskey_key_tags,omitempty"`
+	OrphanedDS      []uint16 `json:"orphaned_ds,omitempty"`
+	DeleteRequested bool     `json:"delete_requested"`
+	Issues          []string `json:"issues,omitempty"`
+}
+
+// DNSSECKey is one DNSKEY of the zone.
+type DNSSECKey struct {
+	KeyTag    uint16 `json:"key_tag"`
+	Role      string `json:"role"`
+	Algorithm string `json:"algorithm"`
+	Bits      int    `json:"bits,omitempty"`
+}
+
+// DNSSECDS is one DS record the parent zone publishes for the zone.
+type DNSSECDS struct {
+	KeyTag     uint16 `json:"key_tag"`
+	Algorithm  string `json:"algorithm"`
+	DigestType string `json:"digest_type"`
+}
+
+// DNSSECSignature is one RRSIG and how close it is to expiring.
+type DNSSECSignature struct {
+	TypeCovered   string    `json:"type_covered"`
+	KeyTag        uint16    `json:"key_tag"`
+	Algorithm     string    `json:"algorithm"`
+	Inception     time.Time `json:"inception"`
+	ExpiresAt     time.Time `json:"expires_at"`
+	ExpiresInDays int       `json:"expires_in_days"`
+	Status        string    `json:"status"`
+}
+
+// DNSSECDenial describes how a signed zone proves that a name does not
+// exist, and whether that proof exposes the zone's contents.
+type DNSSECDenial struct {
+	Method     string   `json:"method"`
+	Walkable   bool     `json:"walkable"`
+	Iterations uint16   `json:"nsec3_iterations,omitempty"`
+	Salt       string   `json:"nsec3_salt,omitempty"`
+	OptOut     bool     `json:"nsec3_opt_out,omitempty"`
+	Issues     []string `json:"issues,omitempty"`
+}
+
+// DNSSECZone describes one link of the DNSSEC chain of trust, from the root
+// trust anchor down to the zone containing the scanned domain.
+type DNSSECZone struct {
+	Zone      string   `json:"zone"`
+	Status    string   `json:"status"`
+	DSKeyTags []uint16 `json:"ds_key_tags,omitempty"`
+	KeyTags   []uint16 `json:"dnskey_key_tags,omitempty"`
+	Error     string   `json:"error,omitempty"`
 }
 
 type Certificates struct {
@@ -53,8 +337,36 @@ type Certificates struct {
 }
 
 type Findings struct {
-	HTTP  HTTPFindings  `json:"http"`
-	Email EmailFindings `json:"email"`
+	HTTP     HTTPFindings    `json:"http"`
+	Email    EmailFindings   `json:"email"`
+	Takeover TakeoverFinding `json:"takeover"`
+	SOA      SOAFindings     `json:"soa"`
+}
+
+// SOAFindings holds the zone's SOA record and the ways it departs from
+// RFC 1912 recommendations.
+type SOAFindings struct {
+	Zone    string   `json:"zone,omitempty"`
+	MName   string   `json:"mname,omitempty"`
+	RName   string   `json:"rname,omitempty"`
+	Serial  uint32   `json:"serial,omitempty"`
+	Refresh uint32   `json:"refresh,omitempty"`
+	Retry   uint32   `json:"retry,omitempty"`
+	Expire  uint32   `json:"expire,omitempty"`
+	Minimum uint32   `json:"minimum,omitempty"`
+	Issues  []string `json:"issues,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// TakeoverFinding reports whether the domain's CNAME chain is dangling, so
+// that someone else could claim the resource it points to.
+type TakeoverFinding struct {
+	CNAMEChain []string `json:"cname_chain,omitempty"`
+	Vulnerable bool     `json:"vulnerable"`
+	Severity   string   `json:"severity,omitempty"`
+	Service    string   `json:"service,omitempty"`
+	Reason     string   `json:"reason,omitempty"`
+	Error      string   `json:"error,omitempty"`
 }
 
 type EmailFindings struct {
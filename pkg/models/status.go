@@ -7,18 +7,42 @@ const (
 	StatusActive       = "Active"
 	StatusExpired      = "Expired"
 	StatusExpiringSoon = "Expiring Soon"
+	StatusUnregistered = "Unregistered"
 )
 
 // ExpirationThresholdDays is the number of days before expiration
 // when the status changes from Active to Expiring Soon.
 const ExpirationThresholdDays = 30
 
+// SignatureExpirationThresholdDays is the same threshold for DNSSEC
+// signatures, which are valid for days or weeks and re-signed well before
+// they expire.
+const SignatureExpirationThresholdDays = 1
+
+// NewlyRegisteredThresholdDays is the domain age in days under which a
+// domain is flagged as newly registered.
+const NewlyRegisteredThresholdDays = 30
+
+// RecentlyUpdatedThresholdDays is the number of days after a change to the
+// registration during which it is flagged as recently updated.
+const RecentlyUpdatedThresholdDays = 7
+
 // CalculateExpirationStatus determines the status based on an expiration timestamp.
 // It returns:
 // - StatusExpired if the timestamp is in the past
 // - StatusExpiringSoon if the timestamp is within ExpirationThresholdDays
 // - StatusActive otherwise
 func CalculateExpirationStatus(expiresAt time.Time) string {
+	return calculateStatus(expiresAt, ExpirationThresholdDays)
+}
+
+// CalculateSignatureStatus determines the status of a DNSSEC signature like
+// CalculateExpirationStatus, using SignatureExpirationThresholdDays.
+func CalculateSignatureStatus(expiresAt time.Time) string {
+	return calculateStatus(expiresAt, SignatureExpirationThresholdDays)
+}
+
+func calculateStatus(expiresAt time.Time, thresholdDays int) string {
 	if expiresAt.IsZero() {
 		return StatusActive
 	}
@@ -28,7 +52,7 @@ func CalculateExpirationStatus(expiresAt time.Time) string {
 		return StatusExpired
 	}
 
-	thresholdTime := now.Add(ExpirationThresholdDays * 24 * time.Hour)
+	thresholdTime := now.Add(time.Duration(thresholdDays) * 24 * time.Hour)
 	if thresholdTime.After(expiresAt) {
 		return StatusExpiringSoon
 	}
@@ -44,3 +68,76 @@ func CalculateDaysUntilExpiration(expiresAt time.Time) int {
 	}
 	return int(time.Until(expiresAt).Hours() / 24)
 }
+
+// CalculateDaysSince calculates the number of whole days since a timestamp,
+// such as a domain's creation date. Returns 0 for a zero timestamp.
+func CalculateDaysSince(t time.Time) int {
+	if t.IsZero() {
+		return 0
+	}
+	return int(time.Since(t).Hours() / 24)
+}
+
+// IsNewlyRegistered reports whether a domain created at createdAt is
+// younger than NewlyRegisteredThresholdDays.
+func IsNewlyRegistered(createdAt time.Time) bool {
+	return isWithinDays(createdAt, NewlyRegisteredThresholdDays)
+}
+
+// IsRecentlyUpdated reports whether a registration last changed at updatedAt
+// changed within RecentlyUpdatedThresholdDays.
+func IsRecentlyUpdated(updatedAt time.Time) bool {
+	return isWithinDays(updatedAt, RecentlyUpdatedThresholdDays)
+}
+
+func isWithinDays(t time.Time, days int) bool {
+	if t.IsZero() {
+		return false
+	}
+	return time.Since(t) < time.Duration(days)*24*time.Hour
+}
+
+// DNSSEC chain-of-trust statuses for each zone walked during validation.
+const (
+	DNSSECStatusSecure   = "secure"
+	DNSSECStatusInsecure = "insecure"
+	DNSSECStatusBogus    = "bogus"
+)
+
+// DNSSEC key roles, by the SEP flag of the DNSKEY.
+const (
+	DNSSECRoleKSK = "KSK"
+	DNSSECRoleZSK = "ZSK"
+)
+
+// Key rollover states, from comparing the parent's DS records with the
+// CDS/CDNSKEY records of the zone.
+const (
+	RolloverInSync  = "in sync"
+	RolloverPending = "pending"
+	RolloverStuck   = "stuck"
+	RolloverDelete  = "delete requested"
+)
+
+// Denial-of-existence methods of DNSSEC-signed zones.
+const (
+	DenialNSEC  = "NSEC"
+	DenialNSEC3 = "NSEC3"
+)
+
+// OwnerRedacted is reported as the owner of a domain whose registrant is
+// hidden behind a privacy service or redacted by the registry.
+const OwnerRedacted = "redacted"
+
+// Address families reported for each A/AAAA record.
+const (
+	AddressFamilyIPv4 = "ipv4"
+	AddressFamilyIPv6 = "ipv6"
+)
+
+// Severities attached to findings.
+const (
+	SeverityHigh   = "high"
+	SeverityMedium = "medium"
+	SeverityLow    = "low"
+)